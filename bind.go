@@ -0,0 +1,227 @@
+package ygggo_env
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Bind 根据结构体字段的标签从环境变量中读取配置，v 必须是指向结构体的非 nil 指针
+//
+// 支持的标签：
+//   - env:"DB_HOST"    字段对应的环境变量名，"-" 表示忽略该字段
+//   - default:"value"  环境变量不存在或为空时使用的值
+//   - required:"true"  环境变量不存在或为空且没有默认值时报错
+//   - prefix:"DB_"     用于嵌套结构体，给其中所有字段的环境变量名加上前缀
+//   - sep:","          切片和字典中元素的分隔符，默认为逗号
+//   - kvsep:":"        字典中键和值的分隔符，默认为冒号
+//   - quotes:"true"    切片和字典的元素可以用引号包裹，引号中的分隔符不拆分，与 WithQuotes 相同
+//   - validate:"rules" 校验规则，写法与 Rules 相同，例如 "min=1;max=65535"
+//
+// 字段类型支持 Get 支持的所有类型，以及它们的指针、切片和字典。
+// 切片和字典的写法与 GetArr、GetDurations、GetMapOf 等函数相同，也可以写成 JSON 数组和 JSON 对象。
+// 指针字段只有在环境变量存在或有默认值时才会被赋值，因此可以用来区分未设置的可选项。
+// 没有 env 标签的结构体字段会被当作嵌套配置递归处理；为 nil 的嵌套结构体指针只有在其中
+// 至少一个字段被赋值时才会分配，否则保持 nil，其中字段的 required 和校验规则也不会报错。
+// 嵌套结构体直接或间接包含自身类型时，内层的同类型字段会被跳过。
+//
+// 环境变量不存在且没有默认值的字段保持原值。
+// 所有字段的错误会合并为一个错误返回，可以用 errors.As 取出其中的 *ValueError，
+// 校验规则发现的问题会合并为一个 Violations。
+func Bind(v interface{}) error {
+	return std.Bind(v)
+}
+
+// Bind 与包级函数 Bind 相同，但从当前对象的变量中读取
+func (g getter) Bind(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind requires a non-nil pointer to a struct, got %T", v)
+	}
+
+	b := &binder{src: g, path: make(map[reflect.Type]bool)}
+	b.bindStruct(rv.Elem(), "")
+	if len(b.violations) > 0 {
+		b.errs = append(b.errs, b.violations)
+	}
+	return errors.Join(b.errs...)
+}
+
+// binder 收集绑定过程中的错误和校验问题
+// path 记录当前正在绑定的结构体类型，用于跳过包含自身类型的字段
+type binder struct {
+	src        Lookuper
+	errs       []error
+	violations Violations
+	path       map[reflect.Type]bool
+}
+
+// bindStruct 绑定结构体的所有字段，prefix 是外层结构体累积的前缀
+// 返回是否有字段从环境变量或默认值中得到了值
+func (b *binder) bindStruct(rv reflect.Value, prefix string) bool {
+	t := rv.Type()
+	b.path[t] = true
+	defer delete(b.path, t)
+
+	set := false
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, tagged := sf.Tag.Lookup("env")
+		if name == "-" {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if !sf.IsExported() {
+			// 未导出的嵌入结构体中的导出字段仍然可以赋值
+			if sf.Anonymous && sf.Type.Kind() == reflect.Struct && isNested(sf.Type) {
+				set = b.bindNested(fv, prefix+sf.Tag.Get("prefix")) || set
+			}
+			continue
+		}
+		if !tagged || name == "" {
+			if isNested(sf.Type) {
+				set = b.bindNested(fv, prefix+sf.Tag.Get("prefix")) || set
+			}
+			continue
+		}
+
+		set = b.bindField(fv, prefix+name, sf.Tag) || set
+	}
+	return set
+}
+
+// bindNested 绑定嵌套结构体，已经在绑定路径上的类型会被跳过
+// 指针为 nil 时先绑定到临时对象，只有其中有字段得到值时才赋给指针
+func (b *binder) bindNested(fv reflect.Value, prefix string) bool {
+	if fv.Kind() != reflect.Pointer {
+		if b.path[fv.Type()] {
+			return false
+		}
+		return b.bindStruct(fv, prefix)
+	}
+	if !fv.CanSet() || b.path[fv.Type().Elem()] {
+		return false
+	}
+	if !fv.IsNil() {
+		return b.bindStruct(fv.Elem(), prefix)
+	}
+
+	errs, violations := len(b.errs), len(b.violations)
+	tmp := reflect.New(fv.Type().Elem())
+	if !b.bindStruct(tmp.Elem(), prefix) {
+		b.errs, b.violations = b.errs[:errs], b.violations[:violations]
+		return false
+	}
+	fv.Set(tmp)
+	return true
+}
+
+// bindField 从环境变量 key 中读取值，校验后赋给字段
+// 返回环境变量或默认值是否非空，值无法解析时也返回 true
+func (b *binder) bindField(fv reflect.Value, key string, tag reflect.StructTag) bool {
+	value := getenv(b.src, key)
+	if value == "" {
+		value = tag.Get("default")
+	}
+	if rules := tag.Get("validate"); rules != "" {
+		b.violations = append(b.violations, validateValue(b.src, key, value, rules)...)
+	}
+	if value == "" {
+		if tag.Get("required") == "true" {
+			b.errs = append(b.errs, fmt.Errorf("%w: %s", ErrNotSet, key))
+		}
+		return false
+	}
+
+	var opts []ListOption
+	if sep := tag.Get("sep"); sep != "" {
+		opts = append(opts, WithSep(sep))
+	}
+	if kvsep := tag.Get("kvsep"); kvsep != "" {
+		opts = append(opts, WithKVSep(kvsep))
+	}
+	if tag.Get("quotes") == "true" {
+		opts = append(opts, WithQuotes())
+	}
+
+	result, err := parseField(value, fv.Type(), newListOptions(opts))
+	if err != nil {
+		b.errs = append(b.errs, &ValueError{Key: key, Value: value, Type: fv.Type().String(), Err: err})
+		return true
+	}
+	fv.Set(result)
+	return true
+}
+
+// parseField 在 parseValue 的基础上支持指针、切片和字典，切片和字典的元素也可以是这些类型
+func parseField(value string, t reflect.Type, o listOptions) (reflect.Value, error) {
+	if isLeaf(t) {
+		return parseValue(value, t)
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, err := parseField(value, t.Elem(), o)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	case reflect.Slice:
+		return parseSlice(strings.TrimSpace(value), t, o, fieldParser(o))
+	case reflect.Map:
+		return parseMapValue(strings.TrimSpace(value), t, o, fieldParser(o))
+	}
+
+	return parseValue(value, t)
+}
+
+// fieldParser 返回 Bind 使用的元素解析方式
+// JSON 中的指针、切片和字典元素交给 parseField 递归解析，其他元素与 Get 系列函数相同
+func fieldParser(o listOptions) elementParser {
+	return elementParser{
+		text: func(value string, t reflect.Type) (reflect.Value, error) {
+			return parseField(value, t, o)
+		},
+		json: func(raw json.RawMessage, t reflect.Type) (reflect.Value, error) {
+			switch t.Kind() {
+			case reflect.Pointer, reflect.Slice, reflect.Map:
+				if !isLeaf(t) {
+					var s string
+					if err := json.Unmarshal(raw, &s); err != nil {
+						s = string(raw)
+					}
+					return parseField(s, t, o)
+				}
+			}
+			return parseJSONElement(raw, t, o.number)
+		},
+	}
+}
+
+// isLeaf 判断 t 是否可以由 parseValue 直接解析，而不是按容器或嵌套结构体处理
+func isLeaf(t reflect.Type) bool {
+	if _, ok := parsers.Load(t); ok {
+		return true
+	}
+	switch t {
+	case durationType, timeType, urlType, ipType, addrType, prefixType, regexpType:
+		return true
+	}
+	return t.Implements(textUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// isNested 判断没有 env 标签的字段是否是需要递归绑定的嵌套结构体
+func isNested(t reflect.Type) bool {
+	if isLeaf(t) {
+		return false
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !isLeaf(t)
+}
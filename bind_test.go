@@ -0,0 +1,229 @@
+package ygggo_env
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindDB struct {
+	Host    string        `env:"HOST" default:"localhost"`
+	Port    int           `env:"PORT" required:"true"`
+	Timeout time.Duration `env:"TIMEOUT" default:"5s"`
+}
+
+type bindLogging struct {
+	Level level `env:"LOG_LEVEL" default:"debug"`
+}
+
+type bindConfig struct {
+	bindLogging
+	DB       bindDB            `prefix:"DB_"`
+	Replica  *bindDB           `prefix:"REPLICA_"`
+	Name     string            `env:"APP_NAME"`
+	Debug    *bool             `env:"APP_DEBUG"`
+	Workers  *int              `env:"APP_WORKERS"`
+	Hosts    []string          `env:"APP_HOSTS"`
+	Ports    []int             `env:"APP_PORTS" sep:";"`
+	Labels   map[string]string `env:"APP_LABELS"`
+	Weights  map[string]int    `env:"APP_WEIGHTS" sep:";" kvsep:"="`
+	Endpoint *url.URL          `env:"APP_ENDPOINT"`
+	Started  time.Time         `env:"APP_STARTED"`
+	Ignored  string            `env:"-"`
+	internal string
+}
+
+func TestBind(t *testing.T) {
+	t.Setenv("DB_PORT", "5432")
+	t.Setenv("DB_TIMEOUT", "")
+	t.Setenv("REPLICA_HOST", "replica.local")
+	t.Setenv("REPLICA_PORT", "5433")
+	t.Setenv("LOG_LEVEL", "info")
+	t.Setenv("APP_NAME", "ygg")
+	t.Setenv("APP_DEBUG", "yes")
+	t.Setenv("APP_HOSTS", "a, b ,c")
+	t.Setenv("APP_PORTS", "80;443")
+	t.Setenv("APP_LABELS", `{"team": "core"}`)
+	t.Setenv("APP_WEIGHTS", "a=1;b=2")
+	t.Setenv("APP_ENDPOINT", "https://example.com")
+	t.Setenv("APP_STARTED", "2024-01-02T03:04:05Z")
+
+	cfg := bindConfig{Name: "preset", Ignored: "keep", internal: "keep"}
+	if err := Bind(&cfg); err != nil {
+		t.Fatalf("Bind() failed: %v", err)
+	}
+
+	if cfg.DB != (bindDB{Host: "localhost", Port: 5432, Timeout: 5 * time.Second}) {
+		t.Errorf("DB = %+v", cfg.DB)
+	}
+	if cfg.Replica == nil || cfg.Replica.Host != "replica.local" || cfg.Replica.Port != 5433 {
+		t.Errorf("Replica = %+v", cfg.Replica)
+	}
+	if cfg.Level != 1 {
+		t.Errorf("Level = %d, want 1", cfg.Level)
+	}
+	if cfg.Name != "ygg" {
+		t.Errorf("Name = %q, want ygg", cfg.Name)
+	}
+	if cfg.Debug == nil || !*cfg.Debug {
+		t.Errorf("Debug = %v, want pointer to true", cfg.Debug)
+	}
+	if cfg.Workers != nil {
+		t.Errorf("Workers = %v, want nil for unset optional field", *cfg.Workers)
+	}
+	if !reflect.DeepEqual(cfg.Hosts, []string{"a", "b", "c"}) {
+		t.Errorf("Hosts = %v", cfg.Hosts)
+	}
+	if !reflect.DeepEqual(cfg.Ports, []int{80, 443}) {
+		t.Errorf("Ports = %v", cfg.Ports)
+	}
+	if !reflect.DeepEqual(cfg.Labels, map[string]string{"team": "core"}) {
+		t.Errorf("Labels = %v", cfg.Labels)
+	}
+	if !reflect.DeepEqual(cfg.Weights, map[string]int{"a": 1, "b": 2}) {
+		t.Errorf("Weights = %v", cfg.Weights)
+	}
+	if cfg.Endpoint == nil || cfg.Endpoint.Host != "example.com" {
+		t.Errorf("Endpoint = %v", cfg.Endpoint)
+	}
+	if cfg.Started.Year() != 2024 {
+		t.Errorf("Started = %v", cfg.Started)
+	}
+	if cfg.Ignored != "keep" || cfg.internal != "keep" {
+		t.Errorf("ignored fields should be left untouched")
+	}
+}
+
+func TestBind_Errors(t *testing.T) {
+	t.Setenv("REPLICA_PORT", "1")
+	t.Setenv("DB_TIMEOUT", "soon")
+	t.Setenv("APP_PORTS", "80;abc")
+	t.Setenv("APP_WEIGHTS", "a")
+
+	var cfg bindConfig
+	err := Bind(&cfg)
+	if err == nil {
+		t.Fatalf("Bind() should fail")
+	}
+
+	if !errors.Is(err, ErrNotSet) || !strings.Contains(err.Error(), "DB_PORT") {
+		t.Errorf("error should report the missing DB_PORT: %v", err)
+	}
+	for _, key := range []string{"DB_TIMEOUT", "APP_PORTS", "APP_WEIGHTS"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("error should report %s: %v", key, err)
+		}
+	}
+	var ve *ValueError
+	if !errors.As(err, &ve) {
+		t.Errorf("error should contain a *ValueError: %v", err)
+	}
+}
+
+func TestBind_InvalidTarget(t *testing.T) {
+	var cfg bindConfig
+	for _, v := range []interface{}{cfg, (*bindConfig)(nil), new(int)} {
+		if err := Bind(v); err == nil {
+			t.Errorf("Bind(%T) should fail", v)
+		}
+	}
+}
+
+type bindTLS struct {
+	Cert string `env:"CERT" required:"true"`
+	Key  string `env:"KEY" validate:"nonempty"`
+}
+
+type bindNode struct {
+	Name string `env:"NAME"`
+	Next *bindNode
+}
+
+func TestBind_NestedPointer(t *testing.T) {
+	var cfg struct {
+		TLS *bindTLS `prefix:"TLS_"`
+	}
+	if err := Bind(&cfg); err != nil || cfg.TLS != nil {
+		t.Errorf("Bind() with no TLS variables = %+v, %v, want nil pointer and no error", cfg.TLS, err)
+	}
+
+	t.Setenv("TLS_CERT", "cert.pem")
+	if err := Bind(&cfg); cfg.TLS == nil || cfg.TLS.Cert != "cert.pem" || !strings.Contains(fmt.Sprint(err), "TLS_KEY") {
+		t.Errorf("Bind() with TLS_CERT = %+v, %v, want TLS set and TLS_KEY reported", cfg.TLS, err)
+	}
+}
+
+func TestBind_SelfReferential(t *testing.T) {
+	t.Setenv("NAME", "head")
+
+	var node bindNode
+	if err := Bind(&node); err != nil {
+		t.Fatalf("Bind() failed: %v", err)
+	}
+	if node.Name != "head" || node.Next != nil {
+		t.Errorf("Bind() = %+v, want Name head and nil Next", node)
+	}
+}
+
+func TestBind_MatchesGetters(t *testing.T) {
+	vars := map[string]string{
+		"DURATIONS":      `["1s","2s"]`,
+		"DURATION_LIST":  "1s, 2m",
+		"DURATION_MAP":   `{"a":"1s","b":"2m"}`,
+		"DURATION_PAIRS": "a:1s,b:2m",
+		"QUOTED":         `"a,b",c`,
+		"INTS":           "[1, 2, 3]",
+		"BAD_DURATIONS":  `["1s","soon"]`,
+	}
+	env := New(WithVars(vars))
+
+	var cfg struct {
+		Durations     []time.Duration          `env:"DURATIONS"`
+		DurationList  []time.Duration          `env:"DURATION_LIST"`
+		DurationMap   map[string]time.Duration `env:"DURATION_MAP"`
+		DurationPairs map[string]time.Duration `env:"DURATION_PAIRS"`
+		Quoted        []string                 `env:"QUOTED" quotes:"true"`
+		Ints          []int                    `env:"INTS"`
+	}
+	if err := env.Bind(&cfg); err != nil {
+		t.Fatalf("Bind() failed: %v", err)
+	}
+
+	durations, _, _ := env.LookupDurations("DURATIONS")
+	durationList, _, _ := env.LookupDurations("DURATION_LIST")
+	durationMap, _, _ := LookupMapOfFrom[time.Duration](env, "DURATION_MAP")
+	durationPairs, _, _ := LookupMapOfFrom[time.Duration](env, "DURATION_PAIRS")
+	quoted, _, _ := env.LookupArr("QUOTED", WithQuotes())
+	ints, _, _ := env.LookupInts("INTS")
+
+	checks := []struct {
+		name      string
+		bound     interface{}
+		getter    interface{}
+		wantValue interface{}
+	}{
+		{"DURATIONS", cfg.Durations, durations, []time.Duration{time.Second, 2 * time.Second}},
+		{"DURATION_LIST", cfg.DurationList, durationList, []time.Duration{time.Second, 2 * time.Minute}},
+		{"DURATION_MAP", cfg.DurationMap, durationMap, map[string]time.Duration{"a": time.Second, "b": 2 * time.Minute}},
+		{"DURATION_PAIRS", cfg.DurationPairs, durationPairs, map[string]time.Duration{"a": time.Second, "b": 2 * time.Minute}},
+		{"QUOTED", cfg.Quoted, quoted, []string{"a,b", "c"}},
+		{"INTS", cfg.Ints, ints, []int{1, 2, 3}},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.bound, c.getter) || !reflect.DeepEqual(c.bound, c.wantValue) {
+			t.Errorf("%s: Bind() = %v, getter = %v, want %v", c.name, c.bound, c.getter, c.wantValue)
+		}
+	}
+
+	var bad struct {
+		Durations []time.Duration `env:"BAD_DURATIONS"`
+	}
+	_, _, getterErr := env.LookupDurations("BAD_DURATIONS")
+	if err := env.Bind(&bad); err == nil || getterErr == nil {
+		t.Errorf("Bind() = %v, LookupDurations() = %v, both should reject invalid elements", err, getterErr)
+	}
+}
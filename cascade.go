@@ -0,0 +1,64 @@
+package ygggo_env
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DefaultModeKeys 是 LoadCascade 默认读取运行模式的环境变量，按顺序取第一个非空值
+var DefaultModeKeys = []string{"APP_ENV", "GO_ENV"}
+
+// LoadCascade 按运行模式加载一组层叠的环境变量文件
+// 运行模式从 modeKeys 中第一个非空的环境变量读取，不传时使用 DefaultModeKeys。
+// 文件从当前目录向上查找，在最近的包含其中任意文件的目录中按以下优先级加载：
+//
+//	.env.{mode}.local > .env.local > .env.{mode} > .env
+//
+// 运行模式为 test 时跳过 .local 文件，保证测试结果不受个人配置影响。
+// 与 Load 相同，已经存在的环境变量优先级最高，不存在的文件会被忽略
+func LoadCascade(modeKeys ...string) error {
+	if len(modeKeys) == 0 {
+		modeKeys = DefaultModeKeys
+	}
+
+	mode := ""
+	for _, key := range modeKeys {
+		if value, _ := std.LookupEnv(key); value != "" {
+			mode = value
+			break
+		}
+	}
+
+	names := cascadeFiles(mode)
+	found, err := findEnvFile(names...)
+	if err != nil || found == "" {
+		return err
+	}
+
+	dir := filepath.Dir(found)
+	var paths []string
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			paths = append(paths, path)
+		}
+	}
+
+	_, err = loadFiles(std, osFiles, paths, false)
+	return err
+}
+
+// cascadeFiles 返回指定运行模式下需要加载的文件名，按优先级从高到低排列
+func cascadeFiles(mode string) []string {
+	var names []string
+	if mode != "test" {
+		if mode != "" {
+			names = append(names, ".env."+mode+".local")
+		}
+		names = append(names, ".env.local")
+	}
+	if mode != "" {
+		names = append(names, ".env."+mode)
+	}
+	return append(names, ".env")
+}
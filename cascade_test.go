@@ -0,0 +1,119 @@
+package ygggo_env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadCascade(t *testing.T) {
+	files := map[string]string{
+		".env":                   "YGGGO_C_WINNER=env\nYGGGO_C_BASE=1\n",
+		".env.local":             "YGGGO_C_WINNER=local\nYGGGO_C_LOCAL=1\n",
+		".env.development":       "YGGGO_C_WINNER=development\nYGGGO_C_MODE=1\n",
+		".env.development.local": "YGGGO_C_WINNER=development.local\nYGGGO_C_MODE_LOCAL=1\n",
+		".env.test":              "YGGGO_C_WINNER=test\nYGGGO_C_MODE=1\n",
+		".env.test.local":        "YGGGO_C_WINNER=test.local\nYGGGO_C_MODE_LOCAL=1\n",
+	}
+
+	tests := []struct {
+		name     string
+		modeKey  string
+		mode     string
+		winner   string
+		loaded   []string
+		excluded []string
+	}{
+		{
+			name:     "no mode",
+			winner:   "local",
+			loaded:   []string{"YGGGO_C_BASE", "YGGGO_C_LOCAL"},
+			excluded: []string{"YGGGO_C_MODE", "YGGGO_C_MODE_LOCAL"},
+		},
+		{
+			name:    "development mode from APP_ENV",
+			modeKey: "APP_ENV",
+			mode:    "development",
+			winner:  "development.local",
+			loaded:  []string{"YGGGO_C_BASE", "YGGGO_C_LOCAL", "YGGGO_C_MODE", "YGGGO_C_MODE_LOCAL"},
+		},
+		{
+			name:    "development mode from GO_ENV",
+			modeKey: "GO_ENV",
+			mode:    "development",
+			winner:  "development.local",
+			loaded:  []string{"YGGGO_C_BASE", "YGGGO_C_LOCAL", "YGGGO_C_MODE", "YGGGO_C_MODE_LOCAL"},
+		},
+		{
+			name:     "test mode skips local files",
+			modeKey:  "APP_ENV",
+			mode:     "test",
+			winner:   "test",
+			loaded:   []string{"YGGGO_C_BASE", "YGGGO_C_MODE"},
+			excluded: []string{"YGGGO_C_LOCAL", "YGGGO_C_MODE_LOCAL"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 在子目录中运行，验证向上查找
+			tempDir := chdirTemp(t, "")
+			for name, content := range files {
+				if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to create %s: %v", name, err)
+				}
+			}
+			subDir := filepath.Join(tempDir, "cmd", "app")
+			if err := os.MkdirAll(subDir, 0755); err != nil {
+				t.Fatalf("Failed to create subdirectory: %v", err)
+			}
+			if err := os.Chdir(subDir); err != nil {
+				t.Fatalf("Failed to change directory: %v", err)
+			}
+
+			t.Setenv("APP_ENV", "")
+			t.Setenv("GO_ENV", "")
+			if tt.modeKey != "" {
+				t.Setenv(tt.modeKey, tt.mode)
+			}
+			unsetAfter(t, "YGGGO_C_WINNER", "YGGGO_C_BASE", "YGGGO_C_LOCAL", "YGGGO_C_MODE", "YGGGO_C_MODE_LOCAL")
+
+			if err := LoadCascade(); err != nil {
+				t.Fatalf("LoadCascade() failed: %v", err)
+			}
+
+			if got := os.Getenv("YGGGO_C_WINNER"); got != tt.winner {
+				t.Errorf("YGGGO_C_WINNER = %s, want %s", got, tt.winner)
+			}
+			for _, key := range tt.loaded {
+				if os.Getenv(key) != "1" {
+					t.Errorf("%s should be loaded", key)
+				}
+			}
+			for _, key := range tt.excluded {
+				if _, exists := os.LookupEnv(key); exists {
+					t.Errorf("%s should not be loaded", key)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadCascade_CustomModeKey(t *testing.T) {
+	tempDir := chdirTemp(t, "YGGGO_C_WINNER=env\n")
+	err := os.WriteFile(filepath.Join(tempDir, ".env.staging"), []byte("YGGGO_C_WINNER=staging\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create .env.staging: %v", err)
+	}
+
+	t.Setenv("YGGGO_C_STAGE", "staging")
+	unsetAfter(t, "YGGGO_C_WINNER")
+
+	if err := LoadCascade("YGGGO_C_STAGE"); err != nil {
+		t.Fatalf("LoadCascade() failed: %v", err)
+	}
+
+	if got := os.Getenv("YGGGO_C_WINNER"); got != "staging" {
+		t.Errorf("YGGGO_C_WINNER = %s, want staging", got)
+	}
+}
@@ -0,0 +1,202 @@
+package ygggo_env
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// EnumError 表示值不在允许的取值范围内，通常包装在 *ValueError 中
+type EnumError struct {
+	// Value 是实际的值
+	Value string
+	// Allowed 是允许的取值，不含别名
+	Allowed []string
+}
+
+func (e *EnumError) Error() string {
+	return fmt.Sprintf("must be one of %s", strings.Join(e.Allowed, ", "))
+}
+
+// Enum 把字符串映射为 T 类型的值，用于读取取值固定的环境变量，例如日志级别
+// 默认不区分大小写，并忽略首尾空白：
+//
+//	var logLevel = gge.NewEnum(map[string]slog.Level{
+//		"debug":   slog.LevelDebug,
+//		"info":    slog.LevelInfo,
+//		"warning": slog.LevelWarn,
+//		"error":   slog.LevelError,
+//	}).Alias("warn", "warning")
+//
+//	level := logLevel.Get("LOG_LEVEL", slog.LevelInfo)
+//
+// Alias 和 CaseSensitive 会修改 Enum 本身，应该在使用前配置好，配置完成后可以并发使用
+type Enum[T any] struct {
+	names []string
+	// values 按原样保存名称和别名，folded 按小写保存
+	values        map[string]T
+	folded        map[string]T
+	caseSensitive bool
+}
+
+// NewEnum 使用名称到值的映射创建 Enum，错误信息中按名称排序列出允许的取值
+func NewEnum[T any](values map[string]T) *Enum[T] {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	e := newEnum[T](len(values))
+	for _, name := range names {
+		e.add(name, values[name])
+	}
+	return e
+}
+
+// Alias 使 alias 与已有的名称 name 对应同一个值，别名不会出现在错误信息中
+// name 不存在时 panic
+func (e *Enum[T]) Alias(alias, name string) *Enum[T] {
+	value, ok := e.values[name]
+	if !ok {
+		panic(fmt.Sprintf("ygggo_env: alias %q refers to unknown enum value %q", alias, name))
+	}
+	e.values[alias] = value
+	e.folded[strings.ToLower(alias)] = value
+	return e
+}
+
+// CaseSensitive 使 Enum 区分大小写
+func (e *Enum[T]) CaseSensitive() *Enum[T] {
+	e.caseSensitive = true
+	return e
+}
+
+// Get 获取环境变量并转换为对应的值
+// 如果环境变量不存在、为空或不是允许的取值，返回默认值
+func (e *Enum[T]) Get(key string, defaultValue T) T {
+	return e.GetFrom(std, key, defaultValue)
+}
+
+// Lookup 获取环境变量并转换为对应的值
+// 环境变量不存在或为空时返回 ok 为 false；不是允许的取值时返回包装了 *EnumError 的 *ValueError
+func (e *Enum[T]) Lookup(key string) (T, bool, error) {
+	return e.LookupFrom(std, key)
+}
+
+// Must 获取环境变量并转换为对应的值，不存在、为空或不是允许的取值时 panic
+func (e *Enum[T]) Must(key string) T {
+	return must(e.Lookup(key))(key)
+}
+
+// GetFrom 与 Get 相同，但从 l 中读取，例如 *Env
+func (e *Enum[T]) GetFrom(l Lookuper, key string, defaultValue T) T {
+	return orDefault(e.LookupFrom(l, key))(defaultValue)
+}
+
+// LookupFrom 与 Lookup 相同，但从 l 中读取，例如 *Env
+func (e *Enum[T]) LookupFrom(l Lookuper, key string) (T, bool, error) {
+	return lookupWith(l, key, "enum", e.Parse)
+}
+
+// Parse 把字符串转换为对应的值，不是允许的取值时返回 *EnumError
+func (e *Enum[T]) Parse(value string) (T, error) {
+	name := strings.TrimSpace(value)
+	values := e.values
+	if !e.caseSensitive {
+		name = strings.ToLower(name)
+		values = e.folded
+	}
+
+	if v, ok := values[name]; ok {
+		return v, nil
+	}
+	var zero T
+	return zero, &EnumError{Value: value, Allowed: e.names}
+}
+
+func newEnum[T any](size int) *Enum[T] {
+	return &Enum[T]{
+		values: make(map[string]T, size),
+		folded: make(map[string]T, size),
+	}
+}
+
+// add 按顺序添加一个取值
+func (e *Enum[T]) add(name string, value T) {
+	e.names = append(e.names, name)
+	e.values[name] = value
+	e.folded[strings.ToLower(name)] = value
+}
+
+// GetEnum 获取取值固定的字符串类型的环境变量，不区分大小写，返回 allowed 中的写法
+// 例如 allowed 为 "debug", "info" 时，LOG_LEVEL=INFO 返回 "info"
+// 如果环境变量不存在、为空或不是允许的取值，返回默认值
+func GetEnum(key string, defaultValue string, allowed ...string) string {
+	return std.GetEnum(key, defaultValue, allowed...)
+}
+
+// GetEnum 与包级函数 GetEnum 相同，但从当前对象的变量中读取
+func (g getter) GetEnum(key string, defaultValue string, allowed ...string) string {
+	return orDefault(g.LookupEnum(key, allowed...))(defaultValue)
+}
+
+// LookupEnum 获取取值固定的字符串类型的环境变量，不区分大小写，返回 allowed 中的写法
+// 环境变量不存在或为空时返回 ok 为 false；不是允许的取值时返回包装了 *EnumError 的 *ValueError
+func LookupEnum(key string, allowed ...string) (string, bool, error) {
+	return std.LookupEnum(key, allowed...)
+}
+
+// LookupEnum 与包级函数 LookupEnum 相同，但从当前对象的变量中读取
+func (g getter) LookupEnum(key string, allowed ...string) (string, bool, error) {
+	return stringEnum(allowed).LookupFrom(g, key)
+}
+
+// MustEnum 获取取值固定的字符串类型的环境变量，不存在、为空或不是允许的取值时 panic
+func MustEnum(key string, allowed ...string) string {
+	return std.MustEnum(key, allowed...)
+}
+
+// MustEnum 与包级函数 MustEnum 相同，但从当前对象的变量中读取
+func (g getter) MustEnum(key string, allowed ...string) string {
+	return must(g.LookupEnum(key, allowed...))(key)
+}
+
+// stringEnum 创建取值为自身的 Enum，错误信息按 allowed 的顺序列出
+func stringEnum(allowed []string) *Enum[string] {
+	e := newEnum[string](len(allowed))
+	for _, name := range allowed {
+		e.add(name, name)
+	}
+	return e
+}
+
+// boolValues 是 GetBool 等函数识别的布尔值写法
+var boolValues atomic.Pointer[Enum[bool]]
+
+func init() {
+	SetBoolValues([]string{"true", "1", "yes", "on"}, []string{"false", "0", "no", "off"})
+}
+
+// SetBoolValues 设置 GetBool、LookupBool、Get[bool]、Bind 等识别的布尔值写法，不区分大小写
+// 默认值为 true/1/yes/on 和 false/0/no/off，例如可以加上 enabled/disabled：
+//
+//	gge.SetBoolValues(
+//		[]string{"true", "1", "yes", "on", "enabled"},
+//		[]string{"false", "0", "no", "off", "disabled"},
+//	)
+//
+// 可以在任意时刻调用，对之后的读取生效
+func SetBoolValues(trueValues, falseValues []string) {
+	e := newEnum[bool](len(trueValues) + len(falseValues))
+	for i := 0; i < len(trueValues) || i < len(falseValues); i++ {
+		if i < len(trueValues) {
+			e.add(trueValues[i], true)
+		}
+		if i < len(falseValues) {
+			e.add(falseValues[i], false)
+		}
+	}
+	boolValues.Store(e)
+}
@@ -0,0 +1,142 @@
+package ygggo_env
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGetEnum(t *testing.T) {
+	tests := []struct {
+		name     string
+		envValue string
+		expected string
+	}{
+		{name: "exact", envValue: "info", expected: "info"},
+		{name: "case insensitive returns canonical", envValue: " WARN ", expected: "warn"},
+		{name: "not allowed", envValue: "verbose", expected: "info"},
+		{name: "unset", envValue: "", expected: "info"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_ENUM", tt.envValue)
+
+			if got := GetEnum("TEST_ENUM", "info", "debug", "info", "warn"); got != tt.expected {
+				t.Errorf("GetEnum() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLookupEnum_Error(t *testing.T) {
+	t.Setenv("TEST_ENUM", "verbose")
+
+	_, ok, err := LookupEnum("TEST_ENUM", "debug", "info", "warn")
+	if !ok || err == nil {
+		t.Fatalf("LookupEnum() = %v, %v, want error", ok, err)
+	}
+
+	var enumErr *EnumError
+	if !errors.As(err, &enumErr) || strings.Join(enumErr.Allowed, ",") != "debug,info,warn" {
+		t.Fatalf("LookupEnum() error = %v, want *EnumError", err)
+	}
+	for _, s := range []string{"TEST_ENUM", "verbose", "must be one of debug, info, warn"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("error %q should contain %q", err.Error(), s)
+		}
+	}
+}
+
+type testLevel int
+
+const (
+	testDebug testLevel = iota
+	testInfo
+	testWarning
+)
+
+func TestEnum(t *testing.T) {
+	levels := NewEnum(map[string]testLevel{
+		"debug":   testDebug,
+		"info":    testInfo,
+		"warning": testWarning,
+	}).Alias("warn", "warning")
+
+	tests := []struct {
+		envValue string
+		expected testLevel
+		wantErr  bool
+	}{
+		{envValue: "debug", expected: testDebug},
+		{envValue: "Warning", expected: testWarning},
+		{envValue: "WARN", expected: testWarning},
+		{envValue: "trace", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.envValue, func(t *testing.T) {
+			t.Setenv("TEST_LEVEL", tt.envValue)
+
+			level, _, err := levels.Lookup("TEST_LEVEL")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Lookup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if level != tt.expected {
+				t.Errorf("Lookup() = %v, want %v", level, tt.expected)
+			}
+		})
+	}
+
+	t.Setenv("TEST_LEVEL", "trace")
+	if got := levels.Get("TEST_LEVEL", testInfo); got != testInfo {
+		t.Errorf("Get() on invalid value = %v, want default", got)
+	}
+	_, err := levels.Parse("trace")
+	if err == nil || err.Error() != "must be one of debug, info, warning" {
+		t.Errorf("Parse() error = %v, want sorted names without aliases", err)
+	}
+}
+
+func TestEnum_CaseSensitive(t *testing.T) {
+	modes := NewEnum(map[string]int{"A": 1, "a": 2}).CaseSensitive().Alias("first", "A")
+
+	for value, expected := range map[string]int{"A": 1, "a": 2, "first": 1} {
+		if got, err := modes.Parse(value); err != nil || got != expected {
+			t.Errorf("Parse(%q) = %v, %v, want %v", value, got, err, expected)
+		}
+	}
+	if _, err := modes.Parse("FIRST"); err == nil {
+		t.Errorf("Parse(FIRST) should fail when case sensitive")
+	}
+}
+
+func TestEnum_AliasUnknown(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Alias() to an unknown name should panic")
+		}
+	}()
+	NewEnum(map[string]int{"a": 1}).Alias("b", "c")
+}
+
+func TestSetBoolValues(t *testing.T) {
+	t.Cleanup(func() {
+		SetBoolValues([]string{"true", "1", "yes", "on"}, []string{"false", "0", "no", "off"})
+	})
+	SetBoolValues([]string{"enabled", "true"}, []string{"disabled", "false"})
+
+	t.Setenv("TEST_BOOL_WORD", "Enabled")
+	t.Setenv("TEST_BOOL_OLD", "yes")
+
+	if got := GetBool("TEST_BOOL_WORD", false); !got {
+		t.Errorf("GetBool() with custom word = %v, want true", got)
+	}
+	if got := Get("TEST_BOOL_WORD", false); !got {
+		t.Errorf("Get[bool]() with custom word = %v, want true", got)
+	}
+	_, _, err := LookupBool("TEST_BOOL_OLD")
+	if err == nil || !strings.Contains(err.Error(), "must be one of enabled, disabled, true, false") {
+		t.Errorf("LookupBool() error = %v, want the custom words listed", err)
+	}
+}
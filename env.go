@@ -1,110 +1,237 @@
 package ygggo_env
 
 import (
-	"bufio"
-	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
-	"path/filepath"
-	"strconv"
-	"strings"
 )
 
+// LoadReport 记录一次加载的结果
+type LoadReport struct {
+	// Files 是实际加载的文件
+	Files []string
+	// Applied 是写入了环境变量的键
+	Applied []string
+	// Skipped 是因为环境中已经存在而没有写入的键
+	Skipped []string
+}
+
 // LoadEnv 自动查找并加载环境变量文件
 // 从当前目录开始向上查找 .env 文件，找到后解析并设置环境变量
+// 与常见的 dotenv 实现一致，已经存在的环境变量优先，不会被文件中的值覆盖
 func LoadEnv() error {
+	_, err := loadEnv(std, false)
+	return err
+}
+
+// LoadEnvReport 与 LoadEnv 相同，同时返回加载报告
+// 可以通过 LoadReport.Skipped 查看哪些键因为已经存在而被跳过
+func LoadEnvReport() (*LoadReport, error) {
+	return loadEnv(std, false)
+}
+
+// Load 按顺序加载指定的环境变量文件
+// 优先级规则：已经存在的环境变量 > 排在前面的文件 > 排在后面的文件，
+// 也就是说第一个设置某个键的文件生效，常见写法是把覆盖用的文件放在前面：
+//
+//	Load("config/secrets.env", "config/base.env")
+//
+// 文件中的变量引用可以使用前面文件中已经确定的值。
+// 显式指定的文件不存在时返回包含文件名的错误；不传参数时等同于 LoadEnv。
+// 任意文件解析失败时不会写入任何环境变量
+func Load(paths ...string) error {
+	return std.Load(paths...)
+}
+
+// MustLoad 与 Load 相同，但在出错时 panic，适合在 main 或 init 中使用
+func MustLoad(paths ...string) {
+	if err := Load(paths...); err != nil {
+		panic(err)
+	}
+}
+
+// Overload 与 Load 相同，但会用文件中的值覆盖已经存在的环境变量
+// 多个文件设置同一个键时，排在后面的文件生效；不传参数时查找并加载 .env 文件
+func Overload(paths ...string) error {
+	return std.Overload(paths...)
+}
+
+// loadEnv 查找并加载 .env 文件到 e 中，overload 表示是否覆盖已有的环境变量
+func loadEnv(e *Env, overload bool) (*LoadReport, error) {
 	envFile, err := findEnvFile()
 	if err != nil {
-		return err
+		return &LoadReport{}, err
 	}
 
 	// 如果没有找到 .env 文件，不报错（这是正常情况）
 	if envFile == "" {
-		return nil
+		return &LoadReport{}, nil
 	}
 
-	return loadEnvFile(envFile)
+	return loadFiles(e, osFiles, []string{envFile}, overload)
 }
 
 // findEnvFile 从当前目录开始向上查找 .env 文件
-func findEnvFile() (string, error) {
+// 可以传入多个文件名，返回最近的目录中第一个存在的文件；不传时查找 .env
+func findEnvFile(names ...string) (string, error) {
 	currentDir, err := os.Getwd()
 	if err != nil {
 		return "", fmt.Errorf("failed to get current directory: %w", err)
 	}
 
-	// 从当前目录开始向上查找
-	for {
-		envPath := filepath.Join(currentDir, ".env")
+	return findUp(osSearch{}, currentDir, names), nil
+}
 
-		// 检查文件是否存在
-		if _, err := os.Stat(envPath); err == nil {
-			return envPath, nil
-		}
+// readFileFunc 读取文件内容，可以是 os.ReadFile 或基于 fs.FS 的实现
+type readFileFunc func(name string) ([]byte, error)
 
-		// 获取父目录
-		parentDir := filepath.Dir(currentDir)
+// fileSystem 是加载 .env 文件的位置：操作系统的文件系统或 fs.FS
+type fileSystem struct {
+	readFile readFileFunc
+	// stat 返回文件的信息，Watcher 用来判断文件是否发生了变化
+	stat func(name string) (fs.FileInfo, error)
+	// sameFile 为 true 时还会通过 os.SameFile 判断文件是否被替换成了另一个文件
+	sameFile bool
+}
 
-		// 如果已经到达根目录，停止查找
-		if parentDir == currentDir {
-			break
-		}
+// osFiles 是操作系统的文件系统
+var osFiles = fileSystem{readFile: os.ReadFile, stat: os.Stat, sameFile: true}
 
-		currentDir = parentDir
+// fsFiles 把 fs.FS 包装成 fileSystem
+func fsFiles(fsys fs.FS) fileSystem {
+	return fileSystem{
+		readFile: fsReadFile(fsys),
+		stat: func(name string) (fs.FileInfo, error) {
+			return fs.Stat(fsys, name)
+		},
 	}
-
-	// 没有找到 .env 文件
-	return "", nil
 }
 
-// loadEnvFile 加载指定的环境变量文件
-func loadEnvFile(filename string) error {
-	file, err := os.Open(filename)
-	if err != nil {
-		return fmt.Errorf("failed to open env file %s: %w", filename, err)
-	}
-	defer file.Close()
+// loadPlan 是一次加载计划写入的变量，尚未写入
+type loadPlan struct {
+	report  *LoadReport
+	changes []change
+	// records 是所有文件中每一个定义的来源
+	records []provenanceRecord
+}
 
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
+// loadFiles 按顺序解析多个文件，全部解析成功后再写入 env，并记录每个定义的来源
+// overload 为 false 时跳过已经存在的环境变量，并且先加载的文件优先；
+// overload 为 true 时覆盖已有的环境变量，并且后加载的文件优先
+func loadFiles(env *Env, files fileSystem, paths []string, overload bool) (*LoadReport, error) {
+	report := &LoadReport{}
+	err := env.update(func() error {
+		plan, err := planLoad(loadLookup(env.store), files.readFile, paths, overload)
+		report = plan.report
+		if err != nil {
+			return err
+		}
+		if err := env.store.apply(plan.changes); err != nil {
+			return err
+		}
+		for _, c := range plan.changes {
+			report.Applied = append(report.Applied, c.key)
+		}
 
-	for scanner.Scan() {
-		lineNum++
-		line := strings.TrimSpace(scanner.Text())
+		env.provenance.record(plan.records)
+		env.loads.add(loadRecord{files: files, paths: paths, overload: overload}, plan.changes)
+		return nil
+	})
+	return report, err
+}
 
-		// 跳过空行和注释行
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
+// planLoad 按顺序解析多个文件，计算需要写入的变量，lookup 用于查找已经存在的变量
+// 返回的计划中总是包含 report，出错时 report 中是已经解析成功的文件
+func planLoad(lookup func(string) (string, bool), readFile readFileFunc, paths []string, overload bool) (*loadPlan, error) {
+	plan := &loadPlan{report: &LoadReport{}}
+
+	// defs 是所有文件中的每一个定义，winner 是每个键最终写入的定义在 defs 中的下标
+	var defs []provenanceRecord
+	winner := make(map[string]int)
+
+	// pending 保存本次加载将要写入的值，供后面文件中的变量引用使用
+	pending := make(map[string]string)
+	var order []string
+	resolve := func(key string) (string, bool) {
+		if value, ok := pending[key]; ok && overload {
+			return value, true
+		}
+		if value, ok := lookup(key); ok {
+			return value, true
 		}
+		value, ok := pending[key]
+		return value, ok
+	}
 
-		// 解析键值对
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			return fmt.Errorf("invalid line %d in %s: %s", lineNum, filename, line)
+	skipped := make(map[string]bool)
+	for _, path := range paths {
+		// 不覆盖模式下，已有的环境变量和前面文件的值都优先于当前文件
+		opts := parseOptions{lookup: resolve}
+		if !overload {
+			opts = parseOptions{shadow: resolve}
+		}
+		entries, err := readEnvFile(readFile, path, opts)
+		if err != nil {
+			return plan, err
 		}
+		plan.report.Files = append(plan.report.Files, path)
 
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
+		// last 是当前文件中每个键最后一次定义的下标，也就是 finalEntries 保留的那一个
+		last := make(map[string]int)
+		for _, e := range entries {
+			last[e.key] = len(defs)
+			defs = append(defs, provenanceRecord{key: e.key, Provenance: Provenance{File: path, Line: e.line}})
+		}
 
-		// 设置环境变量
-		err := os.Setenv(key, value)
-		if err != nil {
-			return fmt.Errorf("failed to set environment variable %s: %w", key, err)
+		for _, e := range finalEntries(entries) {
+			_, inEnv := lookup(e.key)
+			_, inPending := pending[e.key]
+			if !overload && (inEnv || inPending) {
+				if inEnv && !skipped[e.key] {
+					skipped[e.key] = true
+					plan.report.Skipped = append(plan.report.Skipped, e.key)
+				}
+				continue
+			}
+
+			if !inPending {
+				order = append(order, e.key)
+			}
+			pending[e.key] = e.value
+			winner[e.key] = last[e.key]
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading env file %s: %w", filename, err)
+	plan.changes = make([]change, len(order))
+	existed := make(map[string]bool, len(order))
+	for i, key := range order {
+		plan.changes[i] = change{key: key, value: pending[key]}
+		_, existed[key] = lookup(key)
+	}
+	plan.records = resolveProvenance(defs, winner, existed)
+	return plan, nil
+}
+
+// readEnvFile 读取并解析指定的环境变量文件
+func readEnvFile(readFile readFileFunc, filename string, opts parseOptions) ([]entry, error) {
+	content, err := readFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open env file %s: %w", filename, err)
 	}
 
-	return nil
+	return parseEnv(string(content), filename, opts)
 }
 
 // GetStr 获取字符串类型的环境变量
 // 如果环境变量不存在或为空，返回默认值
 func GetStr(key string, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
+	return std.GetStr(key, defaultValue)
+}
+
+// GetStr 与包级函数 GetStr 相同，但从当前对象的变量中读取
+func (g getter) GetStr(key string, defaultValue string) string {
+	value, ok, _ := g.LookupStr(key)
+	if !ok {
 		return defaultValue
 	}
 	return value
@@ -112,101 +239,76 @@ func GetStr(key string, defaultValue string) string {
 
 // GetInt 获取整数类型的环境变量
 // 如果环境变量不存在、为空或无法转换为整数，返回默认值
+// 需要在值非法时报错请使用 LookupInt 或 MustInt
 func GetInt(key string, defaultValue int) int {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-
-	intValue, err := strconv.Atoi(value)
-	if err != nil {
-		return defaultValue
-	}
+	return std.GetInt(key, defaultValue)
+}
 
-	return intValue
+// GetInt 与包级函数 GetInt 相同，但从当前对象的变量中读取
+func (g getter) GetInt(key string, defaultValue int) int {
+	return orDefault(g.LookupInt(key))(defaultValue)
 }
 
 // GetFloat 获取浮点数类型的环境变量
 // 如果环境变量不存在、为空或无法转换为浮点数，返回默认值
 func GetFloat(key string, defaultValue float64) float64 {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-
-	floatValue, err := strconv.ParseFloat(value, 64)
-	if err != nil {
-		return defaultValue
-	}
+	return std.GetFloat(key, defaultValue)
+}
 
-	return floatValue
+// GetFloat 与包级函数 GetFloat 相同，但从当前对象的变量中读取
+func (g getter) GetFloat(key string, defaultValue float64) float64 {
+	return orDefault(g.LookupFloat(key))(defaultValue)
 }
 
 // GetBool 获取布尔类型的环境变量
 // 支持多种布尔值表示：true/false, 1/0, yes/no, on/off (不区分大小写)
 // 如果环境变量不存在、为空或无法识别为布尔值，返回默认值
 func GetBool(key string, defaultValue bool) bool {
-	value := strings.ToLower(strings.TrimSpace(os.Getenv(key)))
-	if value == "" {
-		return defaultValue
-	}
+	return std.GetBool(key, defaultValue)
+}
 
-	switch value {
-	case "true", "1", "yes", "on":
-		return true
-	case "false", "0", "no", "off":
-		return false
-	default:
-		return defaultValue
-	}
+// GetBool 与包级函数 GetBool 相同，但从当前对象的变量中读取
+func (g getter) GetBool(key string, defaultValue bool) bool {
+	return orDefault(g.LookupBool(key))(defaultValue)
 }
 
 // GetMap 获取字典类型的环境变量
-// 环境变量值应该是有效的 JSON 格式
-// 如果环境变量不存在、为空或无法解析为 JSON，返回默认值
-func GetMap(key string, defaultValue map[string]interface{}) map[string]interface{} {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-
-	var result map[string]interface{}
-	err := json.Unmarshal([]byte(value), &result)
-	if err != nil {
-		return defaultValue
-	}
+// 支持两种格式：
+// 1. JSON 对象：{"host": "localhost", "port": 3306}，数字默认解析为 float64，使用 WithNumber 时为 json.Number
+// 2. 键值对：host:localhost,port:3306，值为字符串，分隔符可以通过 WithSep、WithKVSep 修改
+// 需要其他值类型时使用 GetMapOf
+// 如果环境变量不存在、为空或无法解析，返回默认值
+func GetMap(key string, defaultValue map[string]interface{}, opts ...ListOption) map[string]interface{} {
+	return std.GetMap(key, defaultValue, opts...)
+}
 
-	return result
+// GetMap 与包级函数 GetMap 相同，但从当前对象的变量中读取
+func (g getter) GetMap(key string, defaultValue map[string]interface{}, opts ...ListOption) map[string]interface{} {
+	return orDefault(g.LookupMap(key, opts...))(defaultValue)
 }
 
 // GetArr 获取数组类型的环境变量
 // 支持两种格式：
 // 1. 逗号分隔的字符串：value1,value2,value3
 // 2. JSON 数组格式：["value1", "value2", "value3"]
+// 可以通过 WithSep、WithWhitespace、WithQuotes、WithSkipEmpty、WithUnique 调整拆分方式，
+// 需要其他元素类型时使用 GetInts、GetFloats、GetBools、GetDurations
 // 如果环境变量不存在或为空，返回默认值
-func GetArr(key string, defaultValue []string) []string {
-	value := strings.TrimSpace(os.Getenv(key))
-	if value == "" {
-		return defaultValue
-	}
+func GetArr(key string, defaultValue []string, opts ...ListOption) []string {
+	return std.GetArr(key, defaultValue, opts...)
+}
 
-	// 尝试解析为 JSON 数组
-	if strings.HasPrefix(value, "[") {
-		var result []string
-		err := json.Unmarshal([]byte(value), &result)
-		if err == nil {
-			return result
-		}
-		// 如果 JSON 解析失败，返回默认值
-		return defaultValue
-	}
+// GetArr 与包级函数 GetArr 相同，但从当前对象的变量中读取
+func (g getter) GetArr(key string, defaultValue []string, opts ...ListOption) []string {
+	return orDefault(g.LookupArr(key, opts...))(defaultValue)
+}
 
-	// 按逗号分隔处理
-	parts := strings.Split(value, ",")
-	result := make([]string, len(parts))
-	for i, part := range parts {
-		result[i] = strings.TrimSpace(part)
+// orDefault 把 Lookup 系列函数的结果转换为 Get 系列的行为：不存在或出错时使用默认值
+func orDefault[T any](value T, ok bool, err error) func(defaultValue T) T {
+	return func(defaultValue T) T {
+		if !ok || err != nil {
+			return defaultValue
+		}
+		return value
 	}
-
-	return result
 }
@@ -1,8 +1,11 @@
 package ygggo_env
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -189,3 +192,221 @@ YGGGO_VAR3=value3
 		}
 	}
 }
+
+// chdirTemp 在临时目录中写入 .env 文件并切换到该目录，测试结束后恢复工作目录
+func chdirTemp(t *testing.T, envContent string) string {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	if envContent != "" {
+		err := os.WriteFile(filepath.Join(tempDir, ".env"), []byte(envContent), 0644)
+		if err != nil {
+			t.Fatalf("Failed to create test .env file: %v", err)
+		}
+	}
+
+	originalWd, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(originalWd) })
+
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	return tempDir
+}
+
+func TestLoadEnv_DoesNotOverride(t *testing.T) {
+	chdirTemp(t, `YGGGO_OVR_HOST=localhost
+YGGGO_OVR_PORT=3306
+YGGGO_OVR_URL=mysql://${YGGGO_OVR_HOST}:${YGGGO_OVR_PORT}
+`)
+
+	// 模拟运维人员在 shell 中导出的变量
+	t.Setenv("YGGGO_OVR_HOST", "prod.db")
+	os.Unsetenv("YGGGO_OVR_PORT")
+	os.Unsetenv("YGGGO_OVR_URL")
+	defer os.Unsetenv("YGGGO_OVR_PORT")
+	defer os.Unsetenv("YGGGO_OVR_URL")
+
+	report, err := LoadEnvReport()
+	if err != nil {
+		t.Fatalf("LoadEnvReport() failed: %v", err)
+	}
+
+	if got := os.Getenv("YGGGO_OVR_HOST"); got != "prod.db" {
+		t.Errorf("YGGGO_OVR_HOST = %s, want prod.db", got)
+	}
+	if got := os.Getenv("YGGGO_OVR_PORT"); got != "3306" {
+		t.Errorf("YGGGO_OVR_PORT = %s, want 3306", got)
+	}
+	// 引用应该使用最终生效的值
+	if got := os.Getenv("YGGGO_OVR_URL"); got != "mysql://prod.db:3306" {
+		t.Errorf("YGGGO_OVR_URL = %s, want mysql://prod.db:3306", got)
+	}
+
+	if len(report.Files) != 1 {
+		t.Errorf("report.Files = %v, want one file", report.Files)
+	}
+	if len(report.Skipped) != 1 || report.Skipped[0] != "YGGGO_OVR_HOST" {
+		t.Errorf("report.Skipped = %v, want [YGGGO_OVR_HOST]", report.Skipped)
+	}
+	if len(report.Applied) != 2 {
+		t.Errorf("report.Applied = %v, want 2 keys", report.Applied)
+	}
+}
+
+func TestOverload(t *testing.T) {
+	chdirTemp(t, `YGGGO_OVR_HOST=localhost
+YGGGO_OVR_URL=mysql://${YGGGO_OVR_HOST}
+`)
+
+	t.Setenv("YGGGO_OVR_HOST", "prod.db")
+	os.Unsetenv("YGGGO_OVR_URL")
+	defer os.Unsetenv("YGGGO_OVR_URL")
+
+	if err := Overload(); err != nil {
+		t.Fatalf("Overload() failed: %v", err)
+	}
+
+	if got := os.Getenv("YGGGO_OVR_HOST"); got != "localhost" {
+		t.Errorf("YGGGO_OVR_HOST = %s, want localhost", got)
+	}
+	if got := os.Getenv("YGGGO_OVR_URL"); got != "mysql://localhost" {
+		t.Errorf("YGGGO_OVR_URL = %s, want mysql://localhost", got)
+	}
+}
+
+func TestLoadEnv_DuplicateKeys(t *testing.T) {
+	chdirTemp(t, "YGGGO_DUP=first\nYGGGO_DUP=second\n")
+
+	os.Unsetenv("YGGGO_DUP")
+	defer os.Unsetenv("YGGGO_DUP")
+
+	report, err := LoadEnvReport()
+	if err != nil {
+		t.Fatalf("LoadEnvReport() failed: %v", err)
+	}
+
+	// 同一文件中重复的键以最后一次为准，且不算作被跳过
+	if got := os.Getenv("YGGGO_DUP"); got != "second" {
+		t.Errorf("YGGGO_DUP = %s, want second", got)
+	}
+	if len(report.Skipped) != 0 {
+		t.Errorf("report.Skipped = %v, want empty", report.Skipped)
+	}
+}
+
+// writeEnvFiles 在临时目录中写入多个文件，返回文件名到完整路径的映射
+func writeEnvFiles(t *testing.T, files map[string]string) map[string]string {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	paths := make(map[string]string, len(files))
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+		paths[name] = path
+	}
+	return paths
+}
+
+// unsetAfter 清理测试用的环境变量，并在测试结束后再次清理
+func unsetAfter(t *testing.T, keys ...string) {
+	t.Helper()
+
+	for _, key := range keys {
+		os.Unsetenv(key)
+	}
+	t.Cleanup(func() {
+		for _, key := range keys {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func TestLoad_MultipleFiles(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{
+		"config/base.env":    "YGGGO_M_HOST=localhost\nYGGGO_M_PORT=3306\nYGGGO_M_USER=app\nYGGGO_M_DSN=${YGGGO_M_USER}@${YGGGO_M_HOST}\n",
+		"config/secrets.env": "YGGGO_M_USER=admin\nYGGGO_M_PASS=secret\n",
+	})
+	unsetAfter(t, "YGGGO_M_HOST", "YGGGO_M_PORT", "YGGGO_M_USER", "YGGGO_M_PASS", "YGGGO_M_DSN")
+
+	// 排在前面的文件优先
+	err := Load(paths["config/secrets.env"], paths["config/base.env"])
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"YGGGO_M_HOST": "localhost",
+		"YGGGO_M_PORT": "3306",
+		"YGGGO_M_USER": "admin",
+		"YGGGO_M_PASS": "secret",
+		"YGGGO_M_DSN":  "admin@localhost",
+	}
+	for key, expectedValue := range expected {
+		if actualValue := os.Getenv(key); actualValue != expectedValue {
+			t.Errorf("Expected %s=%s, got %s=%s", key, expectedValue, key, actualValue)
+		}
+	}
+}
+
+func TestOverload_MultipleFiles(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{
+		"base.env":  "YGGGO_M_USER=app\nYGGGO_M_HOST=localhost\n",
+		"local.env": "YGGGO_M_USER=admin\n",
+	})
+	unsetAfter(t, "YGGGO_M_USER", "YGGGO_M_HOST")
+	os.Setenv("YGGGO_M_HOST", "prod.db")
+
+	// 排在后面的文件优先，并且覆盖已有的环境变量
+	err := Overload(paths["base.env"], paths["local.env"])
+	if err != nil {
+		t.Fatalf("Overload() failed: %v", err)
+	}
+
+	if got := os.Getenv("YGGGO_M_USER"); got != "admin" {
+		t.Errorf("YGGGO_M_USER = %s, want admin", got)
+	}
+	if got := os.Getenv("YGGGO_M_HOST"); got != "localhost" {
+		t.Errorf("YGGGO_M_HOST = %s, want localhost", got)
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{
+		"base.env": "YGGGO_M_USER=app\n",
+	})
+	unsetAfter(t, "YGGGO_M_USER")
+
+	missing := filepath.Join(filepath.Dir(paths["base.env"]), "missing.env")
+	err := Load(paths["base.env"], missing)
+	if err == nil {
+		t.Fatalf("Load() should fail when a file is missing")
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Load() error should wrap fs.ErrNotExist, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), missing) {
+		t.Errorf("Load() error should name the missing file, got: %v", err)
+	}
+
+	// 出错时不应该写入任何环境变量
+	if _, exists := os.LookupEnv("YGGGO_M_USER"); exists {
+		t.Errorf("YGGGO_M_USER should not be set after a failed Load()")
+	}
+}
+
+func TestMustLoad(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustLoad() should panic when a file is missing")
+		}
+	}()
+
+	MustLoad(filepath.Join(t.TempDir(), "missing.env"))
+}
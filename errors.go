@@ -0,0 +1,112 @@
+package ygggo_env
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ParseErrorReason 表示解析错误的原因，取值是稳定的字符串，便于编辑器和 CI 工具识别
+type ParseErrorReason string
+
+const (
+	// ReasonMissingEquals 表示记录中缺少 '='
+	ReasonMissingEquals ParseErrorReason = "missing-equals"
+	// ReasonUnterminatedQuote 表示引号没有闭合
+	ReasonUnterminatedQuote ParseErrorReason = "unterminated-quote"
+	// ReasonInvalidKey 表示键名不合法
+	ReasonInvalidKey ParseErrorReason = "invalid-key"
+	// ReasonBadEscape 表示双引号中的转义序列不合法
+	ReasonBadEscape ParseErrorReason = "bad-escape"
+	// ReasonUnexpectedText 表示引号闭合后还有多余的内容
+	ReasonUnexpectedText ParseErrorReason = "unexpected-text"
+	// ReasonBadReference 表示变量引用的写法不合法，例如 ${} 或缺少 }
+	ReasonBadReference ParseErrorReason = "bad-reference"
+	// ReasonRequiredVariable 表示 ${VAR:?message} 引用的变量没有设置
+	ReasonRequiredVariable ParseErrorReason = "required-variable"
+	// ReasonReferenceCycle 表示变量之间存在循环引用
+	ReasonReferenceCycle ParseErrorReason = "reference-cycle"
+)
+
+// ParseError 描述 .env 文件中的一个解析错误
+// 可以通过 errors.As 从 LoadEnv、Load、Parse 等函数返回的错误中取出
+type ParseError struct {
+	// File 是文件名，解析 io.Reader 时为 <input>
+	File string
+	// Line 是从 1 开始的行号
+	Line int
+	// Column 是从 1 开始的列号，按字符计算
+	Column int
+	// Text 是出错的那一行的内容
+	Text string
+	// Reason 是错误原因
+	Reason ParseErrorReason
+	// Msg 是错误的详细描述
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid line %d in %s: %s", e.Line, e.File, e.Msg)
+}
+
+// ParseErrors 是同一次解析中收集到的多个错误，按出现的顺序排列
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap 使 errors.As 和 errors.Is 可以检查其中的每一个错误
+func (e ParseErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Lint 检查指定的文件，收集所有解析错误而不是在第一个错误处停止
+// 文件没有问题时返回 nil，有解析错误时返回 ParseErrors，不修改环境变量
+func Lint(paths ...string) error {
+	var errs ParseErrors
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to open env file %s: %w", path, err)
+		}
+		errs = append(errs, lintContent(string(content), path)...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// LintReader 与 Lint 相同，但检查 r 中的内容，name 用作错误中的文件名
+// 适合编辑器检查尚未保存的内容
+func LintReader(name string, r io.Reader) error {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read env content: %w", err)
+	}
+
+	if errs := lintContent(string(content), name); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// lintContent 解析内容并返回收集到的所有错误
+func lintContent(content, filename string) ParseErrors {
+	_, err := parseEnv(content, filename, parseOptions{lookup: os.LookupEnv, collect: true})
+	if errs, ok := err.(ParseErrors); ok {
+		return errs
+	}
+	return nil
+}
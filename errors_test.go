@@ -0,0 +1,171 @@
+package ygggo_env
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseError_Fields(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    int
+		column  int
+		text    string
+		reason  ParseErrorReason
+	}{
+		{
+			name:    "missing equals",
+			content: "A=1\nFOO BAR=1",
+			line:    2,
+			column:  5,
+			text:    "FOO BAR=1",
+			reason:  ReasonMissingEquals,
+		},
+		{
+			name:    "unterminated quote",
+			content: "A=1\nB=\"open\nC=2",
+			line:    2,
+			column:  3,
+			text:    `B="open`,
+			reason:  ReasonUnterminatedQuote,
+		},
+		{
+			name:    "invalid key",
+			content: "  1KEY=value",
+			line:    1,
+			column:  3,
+			text:    "1KEY=value",
+			reason:  ReasonInvalidKey,
+		},
+		{
+			name:    "bad escape on later line of value",
+			content: "A=\"first\nsecond \\q\"",
+			line:    2,
+			column:  8,
+			text:    `second \q"`,
+			reason:  ReasonBadEscape,
+		},
+		{
+			name:    "column counts characters",
+			content: `NAME="源滚滚" x`,
+			line:    1,
+			column:  12,
+			text:    `NAME="源滚滚" x`,
+			reason:  ReasonUnexpectedText,
+		},
+		{
+			name:    "bad reference",
+			content: `A=x${}`,
+			line:    1,
+			column:  4,
+			text:    `A=x${}`,
+			reason:  ReasonBadReference,
+		},
+		{
+			name:    "required variable",
+			content: "A=1\nB=\"${YGGGO_E_MISSING:?is required}\"",
+			line:    2,
+			column:  4,
+			text:    `B="${YGGGO_E_MISSING:?is required}"`,
+			reason:  ReasonRequiredVariable,
+		},
+		{
+			name:    "reference cycle",
+			content: "A=$B\nB=$A",
+			line:    1,
+			column:  1,
+			text:    "A=$B",
+			reason:  ReasonReferenceCycle,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.content))
+
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("Parse() error = %v, want *ParseError", err)
+			}
+			if pe.File != "<input>" || pe.Line != tt.line || pe.Column != tt.column || pe.Text != tt.text || pe.Reason != tt.reason {
+				t.Errorf("ParseError = {%s %d:%d %q %s}, want {<input> %d:%d %q %s}",
+					pe.File, pe.Line, pe.Column, pe.Text, pe.Reason, tt.line, tt.column, tt.text, tt.reason)
+			}
+		})
+	}
+}
+
+func TestParseError_FromLoad(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{
+		"bad.env": "GOOD=1\nBAD\n",
+	})
+
+	err := Load(paths["bad.env"])
+
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Load() error = %v, want *ParseError", err)
+	}
+	if pe.File != paths["bad.env"] || pe.Line != 2 || pe.Reason != ReasonMissingEquals {
+		t.Errorf("ParseError = %+v, want missing equals at %s:2", pe, paths["bad.env"])
+	}
+}
+
+func TestLint(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{
+		"good.env": "A=1\nB=\"two\"\n",
+		"bad.env":  "A=1\nBAD\n1KEY=x\nC=\"\\q\"\nD=ok\nE=${}\nF=\"open\nG=1\n",
+	})
+
+	if err := Lint(paths["good.env"]); err != nil {
+		t.Errorf("Lint() on a valid file = %v, want nil", err)
+	}
+
+	err := Lint(paths["good.env"], paths["bad.env"])
+	var errs ParseErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Lint() error = %v, want ParseErrors", err)
+	}
+
+	expected := []struct {
+		line   int
+		reason ParseErrorReason
+	}{
+		{2, ReasonMissingEquals},
+		{3, ReasonInvalidKey},
+		{4, ReasonBadEscape},
+		{6, ReasonBadReference},
+		{7, ReasonUnterminatedQuote},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Lint() returned %d errors, want %d:\n%v", len(errs), len(expected), err)
+	}
+	for i, e := range expected {
+		if errs[i].Line != e.line || errs[i].Reason != e.reason {
+			t.Errorf("errs[%d] = line %d %s, want line %d %s", i, errs[i].Line, errs[i].Reason, e.line, e.reason)
+		}
+	}
+
+	// errors.As 也可以取出第一个 *ParseError
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Line != 2 {
+		t.Errorf("errors.As(*ParseError) = %v, want the first error", pe)
+	}
+}
+
+func TestLintReader(t *testing.T) {
+	err := LintReader("editor.env", strings.NewReader("A=$B\nB=$A\nC=${YGGGO_E_MISSING?}\n"))
+
+	var errs ParseErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("LintReader() error = %v, want ParseErrors", err)
+	}
+	if len(errs) != 2 || errs[0].Reason != ReasonReferenceCycle || errs[1].Reason != ReasonRequiredVariable {
+		t.Errorf("LintReader() = %v, want a cycle and a required variable error", err)
+	}
+	if errs[0].File != "editor.env" {
+		t.Errorf("errs[0].File = %s, want editor.env", errs[0].File)
+	}
+}
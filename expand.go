@@ -0,0 +1,262 @@
+package ygggo_env
+
+import (
+	"fmt"
+	"strings"
+)
+
+// refOperators 是 ${VAR<op>word} 支持的操作符，较长的写在前面
+var refOperators = []string{":-", ":?", ":+", "-", "?", "+"}
+
+// valuePart 是值的一个片段：字面文本或变量引用
+type valuePart struct {
+	literal string
+	ref     *varRef
+}
+
+// varRef 表示一个变量引用，语义与 shell 参数展开一致：
+//   - ${VAR}         变量的值
+//   - ${VAR:-word}   VAR 未设置或为空时使用 word
+//   - ${VAR-word}    VAR 未设置时使用 word
+//   - ${VAR:?msg}    VAR 未设置或为空时报错
+//   - ${VAR?msg}     VAR 未设置时报错
+//   - ${VAR:+word}   VAR 已设置且非空时使用 word，否则为空
+//   - ${VAR+word}    VAR 已设置时使用 word，否则为空
+type varRef struct {
+	name string
+	op   string
+	word []valuePart
+
+	// line、column 和 text 是 $ 所在的位置和行内容，用于错误信息
+	line   int
+	column int
+	text   string
+}
+
+// partsBuilder 逐步拼接字面文本和变量引用
+type partsBuilder struct {
+	sb    strings.Builder
+	items []valuePart
+}
+
+func (b *partsBuilder) WriteByte(c byte) error {
+	return b.sb.WriteByte(c)
+}
+
+func (b *partsBuilder) WriteRune(r rune) {
+	b.sb.WriteRune(r)
+}
+
+func (b *partsBuilder) addRef(ref *varRef) {
+	b.flush()
+	b.items = append(b.items, valuePart{ref: ref})
+}
+
+func (b *partsBuilder) flush() {
+	if b.sb.Len() > 0 {
+		b.items = append(b.items, valuePart{literal: b.sb.String()})
+		b.sb.Reset()
+	}
+}
+
+// parts 返回拼接好的片段
+func (b *partsBuilder) parts() []valuePart {
+	b.flush()
+	return b.items
+}
+
+// trimmed 返回去掉末尾空白的片段，用于无引号的值
+func (b *partsBuilder) trimmed() []valuePart {
+	items := b.parts()
+	if n := len(items); n > 0 && items[n-1].ref == nil {
+		items[n-1].literal = strings.TrimRight(items[n-1].literal, " \t\r")
+		if items[n-1].literal == "" {
+			items = items[:n-1]
+		}
+	}
+	return items
+}
+
+// 条目的展开状态
+const (
+	unexpanded = iota
+	expanding
+	expanded
+)
+
+// expander 在整个文件范围内展开变量引用
+// 引用按以下顺序查找：
+//  1. shadow（优先于文件自身定义的值）
+//  2. 文件中当前行之前最近的定义
+//  3. lookup（通常是进程环境变量）
+//  4. 文件中当前行之后的定义
+//
+// 第 4 步允许向后引用，因此可能出现 A 引用 B、B 又引用 A 的环，这种情况会报错
+type expander struct {
+	filename string
+	entries  []entry
+	shadow   func(string) (string, bool)
+	lookup   func(string) (string, bool)
+	state    []int
+	stack    []int
+}
+
+// expandEntries 展开所有条目中的变量引用，结果写入 entry.value
+// opts.collect 为 false 时遇到第一个错误就停止，否则返回所有错误
+func expandEntries(entries []entry, filename string, opts parseOptions) ParseErrors {
+	x := &expander{
+		filename: filename,
+		entries:  entries,
+		shadow:   opts.shadow,
+		lookup:   opts.lookup,
+		state:    make([]int, len(entries)),
+	}
+
+	var errs ParseErrors
+	for i := range entries {
+		if err := x.resolve(i); err != nil {
+			errs = append(errs, err)
+			if !opts.collect {
+				return errs
+			}
+		}
+	}
+	return errs
+}
+
+// resolve 展开第 i 个条目，出错时该条目的值为空，且不会被重复报告
+func (x *expander) resolve(i int) *ParseError {
+	switch x.state[i] {
+	case expanded:
+		return nil
+	case expanding:
+		return x.cycleError(i)
+	}
+
+	x.state[i] = expanding
+	x.stack = append(x.stack, i)
+
+	value, err := x.expand(x.entries[i].parts, i)
+
+	x.stack = x.stack[:len(x.stack)-1]
+	x.entries[i].value = value
+	x.state[i] = expanded
+	return err
+}
+
+// expand 把片段拼接成最终的值，at 是片段所属条目的下标
+func (x *expander) expand(parts []valuePart, at int) (string, *ParseError) {
+	var sb strings.Builder
+	for _, part := range parts {
+		if part.ref == nil {
+			sb.WriteString(part.literal)
+			continue
+		}
+
+		value, err := x.expandRef(part.ref, at)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(value)
+	}
+	return sb.String(), nil
+}
+
+// expandRef 按操作符的语义展开一个变量引用
+func (x *expander) expandRef(ref *varRef, at int) (string, *ParseError) {
+	value, set, err := x.lookupVar(ref.name, at)
+	if err != nil {
+		return "", err
+	}
+
+	switch ref.op {
+	case ":-":
+		if !set || value == "" {
+			return x.expand(ref.word, at)
+		}
+	case "-":
+		if !set {
+			return x.expand(ref.word, at)
+		}
+	case ":+":
+		if set && value != "" {
+			return x.expand(ref.word, at)
+		}
+		return "", nil
+	case "+":
+		if set {
+			return x.expand(ref.word, at)
+		}
+		return "", nil
+	case ":?", "?":
+		if !set || (ref.op == ":?" && value == "") {
+			message, err := x.expand(ref.word, at)
+			if err != nil {
+				return "", err
+			}
+			if message == "" {
+				message = "parameter null or not set"
+			}
+			return "", x.errorf(ref.line, ref.column, ref.text, ReasonRequiredVariable, "%s: %s", ref.name, message)
+		}
+	}
+	return value, nil
+}
+
+// lookupVar 查找变量的值，第二个返回值表示变量是否已设置
+func (x *expander) lookupVar(name string, at int) (string, bool, *ParseError) {
+	if x.shadow != nil {
+		if value, ok := x.shadow(name); ok {
+			return value, true, nil
+		}
+	}
+
+	for j := at - 1; j >= 0; j-- {
+		if x.entries[j].key == name {
+			err := x.resolve(j)
+			return x.entries[j].value, true, err
+		}
+	}
+
+	if x.lookup != nil {
+		if value, ok := x.lookup(name); ok {
+			return value, true, nil
+		}
+	}
+
+	for j := len(x.entries) - 1; j > at; j-- {
+		if x.entries[j].key == name {
+			err := x.resolve(j)
+			return x.entries[j].value, true, err
+		}
+	}
+
+	return "", false, nil
+}
+
+// cycleError 生成循环引用的错误，列出环上的所有变量
+func (x *expander) cycleError(i int) *ParseError {
+	var names []string
+	for k := len(x.stack) - 1; k >= 0; k-- {
+		names = append([]string{x.entries[x.stack[k]].key}, names...)
+		if x.stack[k] == i {
+			break
+		}
+	}
+	names = append(names, x.entries[i].key)
+
+	e := x.entries[i]
+	return x.errorf(e.line, e.column, e.text, ReasonReferenceCycle, "variable reference cycle: %s", strings.Join(names, " -> "))
+}
+
+// errorf 生成位于 line:column 的展开错误，text 是该行的内容
+func (x *expander) errorf(line, column int, text string, reason ParseErrorReason, format string, args ...interface{}) *ParseError {
+	return &ParseError{
+		File:   x.filename,
+		Line:   line,
+		Column: column,
+		Text:   text,
+		Reason: reason,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
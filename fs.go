@@ -0,0 +1,39 @@
+package ygggo_env
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// FindFS 在 fsys 中从 dir 开始逐级向上查找环境变量文件，直到根目录 "."
+// names 是要查找的文件名，不传时查找 .env；返回最近的目录中第一个存在的文件，
+// 没有找到时返回空字符串
+func FindFS(fsys fs.FS, dir string, names ...string) (string, error) {
+	if !fs.ValidPath(dir) {
+		return "", fmt.Errorf("invalid directory %q in file system", dir)
+	}
+	return findUp(fsSearch{fsys: fsys}, dir, names), nil
+}
+
+// LoadFS 从 fsys 中按顺序加载环境变量文件，例如通过 //go:embed 嵌入的默认配置
+// names 是 fsys 中的路径，不传时加载根目录下的 .env；
+// 优先级规则与 Load 相同：已经存在的环境变量优先，排在前面的文件优先
+func LoadFS(fsys fs.FS, names ...string) error {
+	return std.LoadFS(fsys, names...)
+}
+
+// ReadFS 与 Read 相同，但从 fsys 中读取文件，不修改环境变量
+// names 不传时读取根目录下的 .env
+func ReadFS(fsys fs.FS, names ...string) (map[string]string, error) {
+	if len(names) == 0 {
+		names = []string{".env"}
+	}
+	return readFiles(fsReadFile(fsys), names)
+}
+
+// fsReadFile 把 fs.FS 包装成 readFileFunc
+func fsReadFile(fsys fs.FS) readFileFunc {
+	return func(name string) ([]byte, error) {
+		return fs.ReadFile(fsys, name)
+	}
+}
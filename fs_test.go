@@ -0,0 +1,122 @@
+package ygggo_env
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		".env":            {Data: []byte("YGGGO_FS_HOST=localhost\nYGGGO_FS_PORT=3306\n")},
+		"config/prod.env": {Data: []byte("YGGGO_FS_HOST=prod.db\n")},
+	}
+	unsetAfter(t, "YGGGO_FS_HOST", "YGGGO_FS_PORT")
+
+	if err := LoadFS(fsys, "config/prod.env", ".env"); err != nil {
+		t.Fatalf("LoadFS() failed: %v", err)
+	}
+
+	if got := os.Getenv("YGGGO_FS_HOST"); got != "prod.db" {
+		t.Errorf("YGGGO_FS_HOST = %s, want prod.db", got)
+	}
+	if got := os.Getenv("YGGGO_FS_PORT"); got != "3306" {
+		t.Errorf("YGGGO_FS_PORT = %s, want 3306", got)
+	}
+}
+
+func TestLoadFS_DefaultName(t *testing.T) {
+	fsys := fstest.MapFS{
+		".env": {Data: []byte("YGGGO_FS_HOST=localhost\n")},
+	}
+	unsetAfter(t, "YGGGO_FS_HOST")
+
+	if err := LoadFS(fsys); err != nil {
+		t.Fatalf("LoadFS() failed: %v", err)
+	}
+	if got := os.Getenv("YGGGO_FS_HOST"); got != "localhost" {
+		t.Errorf("YGGGO_FS_HOST = %s, want localhost", got)
+	}
+}
+
+func TestLoadFS_MissingFile(t *testing.T) {
+	err := LoadFS(fstest.MapFS{}, "missing.env")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("LoadFS() error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestReadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"defaults.env": {Data: []byte("YGGGO_FS_HOST=localhost\nYGGGO_FS_URL=http://${YGGGO_FS_HOST}\n")},
+	}
+	unsetAfter(t, "YGGGO_FS_HOST", "YGGGO_FS_URL")
+
+	result, err := ReadFS(fsys, "defaults.env")
+	if err != nil {
+		t.Fatalf("ReadFS() failed: %v", err)
+	}
+	if result["YGGGO_FS_URL"] != "http://localhost" {
+		t.Errorf("YGGGO_FS_URL = %s, want http://localhost", result["YGGGO_FS_URL"])
+	}
+	if _, exists := os.LookupEnv("YGGGO_FS_HOST"); exists {
+		t.Errorf("ReadFS() should not set YGGGO_FS_HOST")
+	}
+}
+
+func TestFindFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		".env":                  {Data: []byte("A=1")},
+		"services/api/app.env":  {Data: []byte("A=2")},
+		"services/api/cmd/main": {Data: []byte("")},
+		"services/web/.env":     {Data: []byte("A=3")},
+	}
+
+	tests := []struct {
+		name     string
+		dir      string
+		names    []string
+		expected string
+	}{
+		{
+			name:     "file in start directory",
+			dir:      "services/web",
+			expected: "services/web/.env",
+		},
+		{
+			name:     "file in root",
+			dir:      "services/api/cmd",
+			expected: ".env",
+		},
+		{
+			name:     "alternative names",
+			dir:      "services/api/cmd",
+			names:    []string{"app.env", ".env"},
+			expected: "services/api/app.env",
+		},
+		{
+			name:     "not found",
+			dir:      "services",
+			names:    []string{"missing.env"},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FindFS(fsys, tt.dir, tt.names...)
+			if err != nil {
+				t.Fatalf("FindFS() failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("FindFS(%s) = %q, want %q", tt.dir, result, tt.expected)
+			}
+		})
+	}
+
+	if _, err := FindFS(fsys, "/abs"); err == nil {
+		t.Errorf("FindFS() should reject invalid directory")
+	}
+}
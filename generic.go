@@ -0,0 +1,192 @@
+package ygggo_env
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// parserFunc 把字符串转换为某个类型的值，返回值的动态类型必须是注册时的类型
+type parserFunc func(value string) (interface{}, error)
+
+// parsers 是通过 RegisterParser 注册的自定义解析函数，优先于内置解析
+var parsers sync.Map // map[reflect.Type]parserFunc
+
+// 内置支持的几个特殊类型
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	urlType             = reflect.TypeOf((*url.URL)(nil))
+	ipType              = reflect.TypeOf(net.IP(nil))
+	addrType            = reflect.TypeOf(netip.Addr{})
+	prefixType          = reflect.TypeOf(netip.Prefix{})
+	regexpType          = reflect.TypeOf((*regexp.Regexp)(nil))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// RegisterParser 为类型 T 注册解析函数，之后 Get[T] 和 Lookup[T] 会使用它
+// 注册的函数优先于内置解析，重复注册时后注册的生效
+func RegisterParser[T any](fn func(value string) (T, error)) {
+	parsers.Store(typeOf[T](), parserFunc(func(value string) (interface{}, error) {
+		return fn(value)
+	}))
+}
+
+// Get 获取任意类型的环境变量
+// 如果环境变量不存在、为空或无法转换为 T，返回默认值
+//
+// 支持的类型：string、bool、所有宽度的整数和无符号整数、float32/float64、
+// time.Duration、time.Time (RFC 3339)、*url.URL、net.IP、netip.Addr、netip.Prefix、
+// *regexp.Regexp、实现了 encoding.TextUnmarshaler 的类型，以及通过 RegisterParser 注册的类型
+func Get[T any](key string, defaultValue T) T {
+	return GetFrom(std, key, defaultValue)
+}
+
+// Lookup 获取任意类型的环境变量，支持的类型与 Get 相同
+// 环境变量不存在或为空时返回包装了 ErrNotSet 的错误；无法转换时返回 *ValueError
+func Lookup[T any](key string) (T, error) {
+	return LookupFrom[T](std, key)
+}
+
+// GetFrom 与 Get 相同，但从 l 中读取，例如 *Env
+func GetFrom[T any](l Lookuper, key string, defaultValue T) T {
+	return orDefault(lookupValue[T](l, key))(defaultValue)
+}
+
+// LookupFrom 与 Lookup 相同，但从 l 中读取，例如 *Env
+func LookupFrom[T any](l Lookuper, key string) (T, error) {
+	value, ok, err := lookupValue[T](l, key)
+	if err == nil && !ok {
+		err = fmt.Errorf("%w: %s", ErrNotSet, key)
+	}
+	return value, err
+}
+
+// lookupValue 是 Lookup 系列函数的通用实现
+func lookupValue[T any](l Lookuper, key string) (T, bool, error) {
+	var zero T
+	value := getenv(l, key)
+	if value == "" {
+		return zero, false, nil
+	}
+
+	t := typeOf[T]()
+	result, err := parseValue(value, t)
+	if err != nil {
+		return zero, true, &ValueError{Key: key, Value: value, Type: t.String(), Err: err}
+	}
+	return result.Interface().(T), true, nil
+}
+
+// typeOf 返回 T 的反射类型，T 是接口类型时同样有效
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// parseValue 把字符串转换为 t 类型的值
+func parseValue(value string, t reflect.Type) (reflect.Value, error) {
+	if fn, ok := parsers.Load(t); ok {
+		result, err := fn.(parserFunc)(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if result == nil {
+			return reflect.Zero(t), nil
+		}
+		return reflect.ValueOf(result), nil
+	}
+
+	// 具名类型在按 Kind 处理之前先判断，例如 time.Duration 的 Kind 是 int64
+	switch t {
+	case durationType:
+		d, err := time.ParseDuration(value)
+		return reflect.ValueOf(d), err
+	case timeType:
+		tm, err := time.Parse(time.RFC3339, value)
+		return reflect.ValueOf(tm), err
+	case urlType:
+		u, err := url.Parse(value)
+		return reflect.ValueOf(u), err
+	case ipType:
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return reflect.Value{}, errors.New("invalid IP address")
+		}
+		return reflect.ValueOf(ip), nil
+	case addrType:
+		addr, err := netip.ParseAddr(value)
+		return reflect.ValueOf(addr), err
+	case prefixType:
+		prefix, err := netip.ParsePrefix(value)
+		return reflect.ValueOf(prefix), err
+	case regexpType:
+		re, err := regexp.Compile(value)
+		return reflect.ValueOf(re), err
+	}
+
+	if result, ok, err := parseText(value, t); ok {
+		return result, err
+	}
+
+	result := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		result.SetString(value)
+	case reflect.Bool:
+		b, err := parseBool(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		result.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		result.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(value, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		result.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		result.SetFloat(f)
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type %s", t)
+	}
+	return result, nil
+}
+
+// parseText 使用 encoding.TextUnmarshaler 解析值，第二个返回值表示 t 是否支持这种方式
+// t 本身是指针时分配一个新的对象，否则使用 *t 的方法
+func parseText(value string, t reflect.Type) (reflect.Value, bool, error) {
+	if t.Kind() == reflect.Pointer && t.Implements(textUnmarshalerType) {
+		ptr := reflect.New(t.Elem())
+		err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+		return ptr, true, err
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		ptr := reflect.New(t)
+		err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+		return ptr.Elem(), true, err
+	}
+	return reflect.Value{}, false, nil
+}
+
+// parseBool 识别布尔值，写法由 SetBoolValues 设置，默认为 true/false, 1/0, yes/no, on/off (不区分大小写，忽略首尾空白)
+func parseBool(value string) (bool, error) {
+	return boolValues.Load().Parse(value)
+}
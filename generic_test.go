@@ -0,0 +1,129 @@
+package ygggo_env
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// level 用于测试 encoding.TextUnmarshaler 的支持
+type level int
+
+func (l *level) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
+// celsius 用于测试自定义解析函数
+type celsius float64
+
+func TestGet_Types(t *testing.T) {
+	t.Setenv("TEST_GET_INT8", "-8")
+	t.Setenv("TEST_GET_UINT16", "65535")
+	t.Setenv("TEST_GET_FLOAT32", "1.5")
+	t.Setenv("TEST_GET_DURATION", "1m30s")
+	t.Setenv("TEST_GET_TIME", "2024-01-02T03:04:05Z")
+	t.Setenv("TEST_GET_URL", "https://example.com/path")
+	t.Setenv("TEST_GET_IP", "10.0.0.1")
+	t.Setenv("TEST_GET_ADDR", "::1")
+	t.Setenv("TEST_GET_PREFIX", "192.168.0.0/16")
+	t.Setenv("TEST_GET_REGEXP", "^a+$")
+	t.Setenv("TEST_GET_LEVEL", "info")
+	t.Setenv("TEST_GET_BOOL", "Yes")
+
+	if got := Get[int8]("TEST_GET_INT8", 0); got != -8 {
+		t.Errorf("Get[int8]() = %d, want -8", got)
+	}
+	if got := Get[uint16]("TEST_GET_UINT16", 0); got != 65535 {
+		t.Errorf("Get[uint16]() = %d, want 65535", got)
+	}
+	if got := Get[float32]("TEST_GET_FLOAT32", 0); got != 1.5 {
+		t.Errorf("Get[float32]() = %v, want 1.5", got)
+	}
+	if got := Get[time.Duration]("TEST_GET_DURATION", 0); got != 90*time.Second {
+		t.Errorf("Get[time.Duration]() = %v, want 1m30s", got)
+	}
+	if got := Get[time.Time]("TEST_GET_TIME", time.Time{}); !got.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("Get[time.Time]() = %v", got)
+	}
+	if got := Get[*url.URL]("TEST_GET_URL", nil); got == nil || got.Host != "example.com" {
+		t.Errorf("Get[*url.URL]() = %v", got)
+	}
+	if got := Get[net.IP]("TEST_GET_IP", nil); !got.Equal(net.ParseIP("10.0.0.1")) {
+		t.Errorf("Get[net.IP]() = %v", got)
+	}
+	if got := Get[netip.Addr]("TEST_GET_ADDR", netip.Addr{}); got != netip.IPv6Loopback() {
+		t.Errorf("Get[netip.Addr]() = %v", got)
+	}
+	if got := Get[netip.Prefix]("TEST_GET_PREFIX", netip.Prefix{}); got.Bits() != 16 {
+		t.Errorf("Get[netip.Prefix]() = %v", got)
+	}
+	if got, err := Lookup[*regexp.Regexp]("TEST_GET_REGEXP"); err != nil || !got.MatchString("aaa") {
+		t.Errorf("Lookup[*regexp.Regexp]() = %v, %v", got, err)
+	}
+	if got := Get[level]("TEST_GET_LEVEL", 0); got != 1 {
+		t.Errorf("Get[level]() = %d, want 1", got)
+	}
+	if got := Get("TEST_GET_BOOL", false); !got {
+		t.Errorf("Get[bool]() = %v, want true", got)
+	}
+}
+
+func TestGet_Default(t *testing.T) {
+	t.Setenv("TEST_GET_OVERFLOW", "300")
+	t.Setenv("TEST_GET_BAD_LEVEL", "verbose")
+
+	if got := Get[uint8]("TEST_GET_OVERFLOW", 7); got != 7 {
+		t.Errorf("Get[uint8]() on overflow = %d, want default 7", got)
+	}
+	if got := Get[level]("TEST_GET_BAD_LEVEL", 0); got != 0 {
+		t.Errorf("Get[level]() on invalid value = %d, want default 0", got)
+	}
+	if got := Get("TEST_GET_UNSET", 5*time.Second); got != 5*time.Second {
+		t.Errorf("Get[time.Duration]() on unset = %v, want default", got)
+	}
+}
+
+func TestLookup_Errors(t *testing.T) {
+	t.Setenv("TEST_LOOKUP_DURATION", "10")
+
+	_, err := Lookup[time.Duration]("TEST_LOOKUP_DURATION")
+	var ve *ValueError
+	if !errors.As(err, &ve) || ve.Type != "time.Duration" {
+		t.Errorf("Lookup[time.Duration]() error = %v, want *ValueError for time.Duration", err)
+	}
+
+	_, err = Lookup[int]("TEST_LOOKUP_UNSET")
+	if !errors.Is(err, ErrNotSet) || !strings.Contains(err.Error(), "TEST_LOOKUP_UNSET") {
+		t.Errorf("Lookup[int]() on unset = %v, want ErrNotSet", err)
+	}
+}
+
+func TestRegisterParser(t *testing.T) {
+	RegisterParser(func(value string) (celsius, error) {
+		f, err := strconv.ParseFloat(strings.TrimSuffix(value, "C"), 64)
+		return celsius(f), err
+	})
+	t.Setenv("TEST_PARSER_TEMP", "20.5C")
+	t.Setenv("TEST_PARSER_BAD", "hot")
+
+	if got := Get[celsius]("TEST_PARSER_TEMP", 0); got != 20.5 {
+		t.Errorf("Get[celsius]() = %v, want 20.5", got)
+	}
+	if _, err := Lookup[celsius]("TEST_PARSER_BAD"); err == nil {
+		t.Errorf("Lookup[celsius]() should fail on invalid value")
+	}
+}
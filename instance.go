@@ -0,0 +1,204 @@
+package ygggo_env
+
+import (
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// Env 是一组相互隔离的环境变量，拥有与包级函数相同的 GetStr、LookupInt、Bind 等方法，可以并发使用
+//
+// 使用 New 创建的 Env 默认把变量保存在内存中，加载 .env 文件和读取变量都不会影响进程环境变量，
+// 适合并行测试，以及在同一进程中为不同组件加载不同的配置：
+//
+//	env := gge.New()
+//	if err := env.Load("config/worker.env"); err != nil {
+//		log.Fatal(err)
+//	}
+//	port := env.GetInt("PORT", 8080)
+//
+// 包级函数使用的是基于进程环境变量的默认实例，见 Default
+type Env struct {
+	getter
+	store store
+
+	// provenance 记录每个变量在哪些文件中被定义过
+	provenance *provenanceLog
+	// loads 记录成功的加载，用于重新加载
+	loads *loadLog
+	// snapshots 保存当前的 Snapshot 和 OnChange 注册的回调
+	snapshots *snapshots
+}
+
+// getter 实现了所有读取变量的方法，被 Env 嵌入
+type getter struct {
+	src Lookuper
+}
+
+// LookupEnv 查找变量，第二个返回值表示变量是否存在
+func (g getter) LookupEnv(key string) (string, bool) {
+	return g.src.LookupEnv(key)
+}
+
+// getenv 返回变量的值，不存在时返回空字符串
+func (g getter) getenv(key string) string {
+	return getenv(g.src, key)
+}
+
+// getenv 返回 l 中变量的值，不存在时返回空字符串
+func getenv(l Lookuper, key string) string {
+	value, _ := l.LookupEnv(key)
+	return value
+}
+
+// std 是包级函数使用的默认实例
+var std = newEnv(osStore{})
+
+// Default 返回包级函数使用的默认实例，它直接读写进程环境变量
+func Default() *Env {
+	return std
+}
+
+// Option 是 New 的选项
+type Option func(*envOptions)
+
+type envOptions struct {
+	os      bool
+	vars    map[string]string
+	environ []string
+	sources []Source
+}
+
+// WithOS 使 Env 直接读写进程环境变量，效果与包级函数相同
+func WithOS() Option {
+	return func(o *envOptions) {
+		o.os = true
+	}
+}
+
+// WithVars 设置 Env 的初始变量，可以与 WithEnviron 一起使用，同名时 WithVars 优先
+func WithVars(vars map[string]string) Option {
+	return func(o *envOptions) {
+		if o.vars == nil {
+			o.vars = make(map[string]string, len(vars))
+		}
+		for key, value := range vars {
+			o.vars[key] = value
+		}
+	}
+}
+
+// WithEnviron 使用 KEY=value 格式的列表设置 Env 的初始变量，
+// 例如 WithEnviron(os.Environ()) 从进程环境变量的一份副本开始
+func WithEnviron(environ []string) Option {
+	return func(o *envOptions) {
+		o.environ = append(o.environ, environ...)
+	}
+}
+
+// New 创建一个 Env，不传选项时变量保存在内存中，初始为空
+func New(opts ...Option) *Env {
+	var o envOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var changes []change
+	for _, kv := range o.environ {
+		if key, value, ok := strings.Cut(kv, "="); ok && key != "" {
+			changes = append(changes, change{key: key, value: value})
+		}
+	}
+	keys := make([]string, 0, len(o.vars))
+	for key := range o.vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		changes = append(changes, change{key: key, value: o.vars[key]})
+	}
+
+	var s store = newMapStore(nil)
+	switch {
+	case len(o.sources) > 0:
+		s = newLayeredStore(o.sources)
+	case o.os:
+		s = osStore{}
+	}
+	// 内存中的存储不会写入失败，写入进程环境变量失败的情况也忽略，与 os.Setenv 的常见用法一致；
+	// 只有 WithSources 中没有 Loaded 来源时初始变量无处保存，这是调用方式的错误
+	if err := s.apply(changes); err == errNoLoadedSource {
+		panic("ygggo_env: WithVars and WithEnviron need a Loaded() source in WithSources")
+	}
+	return newEnv(s)
+}
+
+func newEnv(s store) *Env {
+	return &Env{getter: getter{src: s}, store: s, provenance: &provenanceLog{}, loads: &loadLog{}, snapshots: &snapshots{}}
+}
+
+// Set 设置变量，Origin 会报告这个值来自 Set
+func (e *Env) Set(key, value string) error {
+	return e.update(func() error {
+		_, existed := e.store.LookupEnv(key)
+		if err := e.store.apply([]change{{key: key, value: value}}); err != nil {
+			return err
+		}
+		delete(e.loads.values, key)
+		e.provenance.record([]provenanceRecord{{key: key, Provenance: Provenance{Applied: true, Overrode: existed}}})
+		return nil
+	})
+}
+
+// Unset 删除变量，同时清除这个变量的定义记录
+func (e *Env) Unset(key string) error {
+	return e.update(func() error {
+		if err := e.store.apply([]change{{key: key, unset: true}}); err != nil {
+			return err
+		}
+		delete(e.loads.values, key)
+		e.provenance.forget(key)
+		return nil
+	})
+}
+
+// Environ 返回 KEY=value 格式的所有变量，按变量名排序
+func (e *Env) Environ() []string {
+	var environ []string
+	for _, key := range e.store.keys() {
+		if value, ok := e.store.LookupEnv(key); ok {
+			environ = append(environ, key+"="+value)
+		}
+	}
+	return environ
+}
+
+// Load 与包级函数 Load 相同，但把变量写入 Env
+// 不传参数时从当前目录开始向上查找 .env 文件，没有找到时不报错
+func (e *Env) Load(paths ...string) error {
+	_, err := e.load(paths, false)
+	return err
+}
+
+// Overload 与包级函数 Overload 相同，但把变量写入 Env
+func (e *Env) Overload(paths ...string) error {
+	_, err := e.load(paths, true)
+	return err
+}
+
+// LoadFS 与包级函数 LoadFS 相同，但把变量写入 Env
+func (e *Env) LoadFS(fsys fs.FS, names ...string) error {
+	if len(names) == 0 {
+		names = []string{".env"}
+	}
+	_, err := loadFiles(e, fsFiles(fsys), names, false)
+	return err
+}
+
+// load 加载指定的文件，不传文件时查找 .env
+func (e *Env) load(paths []string, overload bool) (*LoadReport, error) {
+	if len(paths) == 0 {
+		return loadEnv(e, overload)
+	}
+	return loadFiles(e, osFiles, paths, overload)
+}
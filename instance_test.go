@@ -0,0 +1,177 @@
+package ygggo_env
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestEnv_LoadIsIsolated(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{
+		"a.env": "TEST_ENV_PORT=8080\nTEST_ENV_URL=http://${TEST_ENV_HOST}:${TEST_ENV_PORT}\n",
+		"b.env": "TEST_ENV_PORT=9090\n",
+	})
+	unsetAfter(t, "TEST_ENV_PORT", "TEST_ENV_URL")
+	t.Setenv("TEST_ENV_HOST", "from-os")
+
+	a := New(WithVars(map[string]string{"TEST_ENV_HOST": "a.local"}))
+	b := New()
+	if err := a.Load(paths["a.env"]); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if err := b.Load(paths["b.env"]); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if got := a.GetInt("TEST_ENV_PORT", 0); got != 8080 {
+		t.Errorf("a.GetInt() = %d, want 8080", got)
+	}
+	if got := b.GetInt("TEST_ENV_PORT", 0); got != 9090 {
+		t.Errorf("b.GetInt() = %d, want 9090", got)
+	}
+	if got := a.GetStr("TEST_ENV_URL", ""); got != "http://a.local:8080" {
+		t.Errorf("a.GetStr() = %q, interpolation should use the Env's own variables", got)
+	}
+	if _, ok := os.LookupEnv("TEST_ENV_PORT"); ok {
+		t.Errorf("Env.Load() should not modify the process environment")
+	}
+	if _, ok := b.LookupEnv("TEST_ENV_HOST"); ok {
+		t.Errorf("New() should start empty, not copy the process environment")
+	}
+}
+
+func TestEnv_Options(t *testing.T) {
+	t.Setenv("TEST_ENV_OS", "1")
+
+	e := New(WithEnviron([]string{"A=1", "B=x=y", "invalid"}), WithVars(map[string]string{"A": "2"}))
+	if got := e.Environ(); !reflect.DeepEqual(got, []string{"A=2", "B=x=y"}) {
+		t.Errorf("Environ() = %v", got)
+	}
+
+	copied := New(WithEnviron(os.Environ()))
+	if got := copied.GetStr("TEST_ENV_OS", ""); got != "1" {
+		t.Errorf("WithEnviron(os.Environ()) GetStr() = %q, want 1", got)
+	}
+
+	osEnv := New(WithOS())
+	if err := osEnv.Set("TEST_ENV_OS", "2"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if got := os.Getenv("TEST_ENV_OS"); got != "2" {
+		t.Errorf("WithOS() Set() should write the process environment, got %q", got)
+	}
+	if Default().GetStr("TEST_ENV_OS", "") != "2" {
+		t.Errorf("Default() should read the process environment")
+	}
+}
+
+func TestEnv_SetUnset(t *testing.T) {
+	e := New()
+	if err := e.Set("KEY", "value"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if got := e.MustStr("KEY"); got != "value" {
+		t.Errorf("MustStr() = %q, want value", got)
+	}
+	if err := e.Unset("KEY"); err != nil {
+		t.Fatalf("Unset() failed: %v", err)
+	}
+	if _, ok := e.LookupEnv("KEY"); ok {
+		t.Errorf("LookupEnv() after Unset() should report missing")
+	}
+}
+
+func TestEnv_Getters(t *testing.T) {
+	e := New(WithVars(map[string]string{
+		"PORT":      "8080",
+		"TIMEOUT":   "5s",
+		"HOSTS":     "a,b",
+		"LIMITS":    "x:1,y:2",
+		"LOG_LEVEL": "WARN",
+		"DB_PORT":   "5432",
+		"TLS":       "true",
+	}))
+
+	if got := GetFrom(e, "TIMEOUT", time.Second); got != 5*time.Second {
+		t.Errorf("GetFrom() = %v", got)
+	}
+	if got, err := LookupFrom[int](e, "PORT"); err != nil || got != 8080 {
+		t.Errorf("LookupFrom() = %v, %v", got, err)
+	}
+	if got := e.GetArr("HOSTS", nil); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("GetArr() = %v", got)
+	}
+	if got := GetMapOfFrom[int](e, "LIMITS", nil); !reflect.DeepEqual(got, map[string]int{"x": 1, "y": 2}) {
+		t.Errorf("GetMapOfFrom() = %v", got)
+	}
+	if got := e.GetEnum("LOG_LEVEL", "info", "info", "warn"); got != "warn" {
+		t.Errorf("GetEnum() = %q", got)
+	}
+	levels := NewEnum(map[string]int{"info": 1, "warn": 2})
+	if got := levels.GetFrom(e, "LOG_LEVEL", 0); got != 2 {
+		t.Errorf("Enum.GetFrom() = %d", got)
+	}
+
+	var cfg struct {
+		Port int    `env:"DB_PORT"`
+		Cert string `env:"TLS_CERT" validate:"required_if=TLS=true"`
+	}
+	err := e.Bind(&cfg)
+	if cfg.Port != 5432 {
+		t.Errorf("Bind() Port = %d, want 5432", cfg.Port)
+	}
+	if err == nil {
+		t.Errorf("Bind() should report TLS_CERT using the Env's TLS value")
+	}
+	if err := e.Validate(Rules{"PORT": "max=1024"}); err == nil {
+		t.Errorf("Validate() should check the Env's PORT")
+	}
+}
+
+func TestEnv_LoadFS(t *testing.T) {
+	fsys := fstest.MapFS{".env": {Data: []byte("NAME=embedded\n")}}
+
+	e := New(WithVars(map[string]string{"NAME": "preset"}))
+	if err := e.LoadFS(fsys); err != nil {
+		t.Fatalf("LoadFS() failed: %v", err)
+	}
+	if got := e.GetStr("NAME", ""); got != "preset" {
+		t.Errorf("LoadFS() should not override existing variables, got %q", got)
+	}
+	if err := e.Overload(); err != nil {
+		t.Fatalf("Overload() without .env failed: %v", err)
+	}
+}
+
+func TestEnv_Concurrent(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{".env": "A=1\nB=2\n"})
+	e := New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				e.Set(fmt.Sprintf("K%d", i), fmt.Sprint(j))
+				e.Overload(paths[".env"])
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				e.GetInt("A", 0)
+				e.Environ()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := e.GetInt("B", 0); got != 2 {
+		t.Errorf("GetInt() = %d, want 2", got)
+	}
+}
@@ -0,0 +1,349 @@
+package ygggo_env
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ListOption 控制数组和字典类型的环境变量如何拆分
+type ListOption func(*listOptions)
+
+type listOptions struct {
+	sep        string
+	whitespace bool
+	quotes     bool
+	skipEmpty  bool
+	unique     bool
+
+	// kvsep 和 number 只用于字典
+	kvsep  string
+	number bool
+}
+
+// WithSep 使用 sep 作为元素的分隔符，默认为逗号，例如 WithSep(";")、WithSep("\n")
+// sep 为空时忽略这个选项
+func WithSep(sep string) ListOption {
+	return func(o *listOptions) {
+		if sep == "" {
+			return
+		}
+		o.sep = sep
+		o.whitespace = false
+	}
+}
+
+// WithWhitespace 按任意空白字符（空格、制表符、换行）拆分，连续的空白视为一个分隔符
+func WithWhitespace() ListOption {
+	return func(o *listOptions) {
+		o.whitespace = true
+	}
+}
+
+// WithQuotes 识别双引号和单引号，引号中的分隔符不拆分，引号本身会被去掉
+// 例如 "a,b",c 拆分为 a,b 和 c
+func WithQuotes() ListOption {
+	return func(o *listOptions) {
+		o.quotes = true
+	}
+}
+
+// WithSkipEmpty 去掉空元素，例如 a,,b 拆分为 a 和 b
+func WithSkipEmpty() ListOption {
+	return func(o *listOptions) {
+		o.skipEmpty = true
+	}
+}
+
+// WithUnique 去掉重复的元素，保留第一次出现的位置
+func WithUnique() ListOption {
+	return func(o *listOptions) {
+		o.unique = true
+	}
+}
+
+// GetInts 获取整数数组类型的环境变量，格式与 GetArr 相同
+// 如果环境变量不存在、为空或任意元素无法转换为整数，返回默认值
+func GetInts(key string, defaultValue []int, opts ...ListOption) []int {
+	return std.GetInts(key, defaultValue, opts...)
+}
+
+// GetInts 与包级函数 GetInts 相同，但从当前对象的变量中读取
+func (g getter) GetInts(key string, defaultValue []int, opts ...ListOption) []int {
+	return orDefault(g.LookupInts(key, opts...))(defaultValue)
+}
+
+// GetFloats 获取浮点数数组类型的环境变量，格式与 GetArr 相同
+// 如果环境变量不存在、为空或任意元素无法转换为浮点数，返回默认值
+func GetFloats(key string, defaultValue []float64, opts ...ListOption) []float64 {
+	return std.GetFloats(key, defaultValue, opts...)
+}
+
+// GetFloats 与包级函数 GetFloats 相同，但从当前对象的变量中读取
+func (g getter) GetFloats(key string, defaultValue []float64, opts ...ListOption) []float64 {
+	return orDefault(g.LookupFloats(key, opts...))(defaultValue)
+}
+
+// GetBools 获取布尔数组类型的环境变量，格式与 GetArr 相同，元素的写法与 GetBool 相同
+// 如果环境变量不存在、为空或任意元素无法识别为布尔值，返回默认值
+func GetBools(key string, defaultValue []bool, opts ...ListOption) []bool {
+	return std.GetBools(key, defaultValue, opts...)
+}
+
+// GetBools 与包级函数 GetBools 相同，但从当前对象的变量中读取
+func (g getter) GetBools(key string, defaultValue []bool, opts ...ListOption) []bool {
+	return orDefault(g.LookupBools(key, opts...))(defaultValue)
+}
+
+// GetDurations 获取时长数组类型的环境变量，元素的写法与 time.ParseDuration 相同，例如 1s,5s,30s
+// 如果环境变量不存在、为空或任意元素无法转换为时长，返回默认值
+func GetDurations(key string, defaultValue []time.Duration, opts ...ListOption) []time.Duration {
+	return std.GetDurations(key, defaultValue, opts...)
+}
+
+// GetDurations 与包级函数 GetDurations 相同，但从当前对象的变量中读取
+func (g getter) GetDurations(key string, defaultValue []time.Duration, opts ...ListOption) []time.Duration {
+	return orDefault(g.LookupDurations(key, opts...))(defaultValue)
+}
+
+// LookupInts 获取整数数组类型的环境变量
+// 环境变量不存在或为空时返回 ok 为 false；任意元素无法转换时返回 *ValueError
+func LookupInts(key string, opts ...ListOption) ([]int, bool, error) {
+	return std.LookupInts(key, opts...)
+}
+
+// LookupInts 与包级函数 LookupInts 相同，但从当前对象的变量中读取
+func (g getter) LookupInts(key string, opts ...ListOption) ([]int, bool, error) {
+	return lookupList[int](g, key, opts)
+}
+
+// LookupFloats 获取浮点数数组类型的环境变量
+// 环境变量不存在或为空时返回 ok 为 false；任意元素无法转换时返回 *ValueError
+func LookupFloats(key string, opts ...ListOption) ([]float64, bool, error) {
+	return std.LookupFloats(key, opts...)
+}
+
+// LookupFloats 与包级函数 LookupFloats 相同，但从当前对象的变量中读取
+func (g getter) LookupFloats(key string, opts ...ListOption) ([]float64, bool, error) {
+	return lookupList[float64](g, key, opts)
+}
+
+// LookupBools 获取布尔数组类型的环境变量
+// 环境变量不存在或为空时返回 ok 为 false；任意元素无法识别时返回 *ValueError
+func LookupBools(key string, opts ...ListOption) ([]bool, bool, error) {
+	return std.LookupBools(key, opts...)
+}
+
+// LookupBools 与包级函数 LookupBools 相同，但从当前对象的变量中读取
+func (g getter) LookupBools(key string, opts ...ListOption) ([]bool, bool, error) {
+	return lookupList[bool](g, key, opts)
+}
+
+// LookupDurations 获取时长数组类型的环境变量
+// 环境变量不存在或为空时返回 ok 为 false；任意元素无法转换时返回 *ValueError
+func LookupDurations(key string, opts ...ListOption) ([]time.Duration, bool, error) {
+	return std.LookupDurations(key, opts...)
+}
+
+// LookupDurations 与包级函数 LookupDurations 相同，但从当前对象的变量中读取
+func (g getter) LookupDurations(key string, opts ...ListOption) ([]time.Duration, bool, error) {
+	return lookupList[time.Duration](g, key, opts)
+}
+
+// lookupList 是数组类型的 Lookup 函数的通用实现
+func lookupList[T comparable](l Lookuper, key string, opts []ListOption) ([]T, bool, error) {
+	value := strings.TrimSpace(getenv(l, key))
+	if value == "" {
+		return nil, false, nil
+	}
+
+	o := newListOptions(opts)
+	listType := reflect.SliceOf(typeOf[T]())
+	result, err := parseSlice(value, listType, o, valueParser(o))
+	if err != nil {
+		return nil, true, &ValueError{Key: key, Value: value, Type: listType.String(), Err: err}
+	}
+	return uniqueIf(result.Interface().([]T), o), true, nil
+}
+
+// newListOptions 应用所有选项，默认按逗号拆分元素，按冒号拆分键和值
+func newListOptions(opts []ListOption) listOptions {
+	o := listOptions{sep: ",", kvsep: ":"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// elementParser 解析列表和字典中的元素
+// text 解析按分隔符拆分得到的文本，json 解析 JSON 数组和对象中的元素
+type elementParser struct {
+	text func(value string, t reflect.Type) (reflect.Value, error)
+	json func(raw json.RawMessage, t reflect.Type) (reflect.Value, error)
+}
+
+// valueParser 返回 Get 系列函数使用的元素解析方式，元素必须是 Get 支持的类型
+func valueParser(o listOptions) elementParser {
+	return elementParser{
+		text: parseValue,
+		json: func(raw json.RawMessage, t reflect.Type) (reflect.Value, error) {
+			return parseJSONElement(raw, t, o.number)
+		},
+	}
+}
+
+// parseSlice 把值解析为切片类型 t，值以 [ 开头时按 JSON 数组解析，否则按 o 拆分
+func parseSlice(value string, t reflect.Type, o listOptions, p elementParser) (reflect.Value, error) {
+	if strings.HasPrefix(value, "[") {
+		var raw []json.RawMessage
+		if err := json.Unmarshal([]byte(value), &raw); err != nil {
+			return reflect.Value{}, err
+		}
+		result := reflect.MakeSlice(t, 0, len(raw))
+		for _, r := range raw {
+			if o.skipEmpty && string(r) == `""` {
+				continue
+			}
+			elem, err := p.json(r, t.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %s: %w", r, err)
+			}
+			result = reflect.Append(result, elem)
+		}
+		return result, nil
+	}
+
+	items, err := splitList(value, o)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	result := reflect.MakeSlice(t, 0, len(items))
+	for _, item := range items {
+		elem, err := p.text(item, t.Elem())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("element %q: %w", item, err)
+		}
+		result = reflect.Append(result, elem)
+	}
+	return result, nil
+}
+
+// splitList 把值拆分为元素，值以 [ 开头时按 JSON 数组解析，否则按 o 拆分
+func splitList(value string, o listOptions) ([]string, error) {
+	var items []string
+	switch {
+	case strings.HasPrefix(value, "["):
+		var raw []json.RawMessage
+		if err := json.Unmarshal([]byte(value), &raw); err != nil {
+			return nil, err
+		}
+		// JSON 数组中的字符串去掉引号，数字、布尔值等保留原样
+		items = make([]string, len(raw))
+		for i, r := range raw {
+			if err := json.Unmarshal(r, &items[i]); err != nil {
+				items[i] = string(r)
+			}
+		}
+	case o.quotes:
+		var err error
+		if items, err = splitQuoted(value, o); err != nil {
+			return nil, err
+		}
+	case o.whitespace:
+		items = strings.Fields(value)
+	default:
+		items = strings.Split(value, o.sep)
+		for i, item := range items {
+			items[i] = strings.TrimSpace(item)
+		}
+	}
+
+	if o.skipEmpty {
+		filtered := items[:0]
+		for _, item := range items {
+			if item != "" {
+				filtered = append(filtered, item)
+			}
+		}
+		items = filtered
+	}
+	return items, nil
+}
+
+// splitQuoted 按分隔符拆分，跳过引号中的分隔符并去掉引号
+// 只有出现在元素开头的引号才起作用，例如 it's 中的单引号按普通字符处理
+func splitQuoted(value string, o listOptions) ([]string, error) {
+	var items []string
+	var sb strings.Builder
+	var quote rune  // 当前所在的引号，0 表示不在引号中
+	quoted := false // 当前元素是否使用了引号
+
+	flush := func() {
+		item := sb.String()
+		// 引号内的空白保留，没有引号的元素去掉首尾空白
+		if !quoted {
+			item = strings.TrimSpace(item)
+		}
+		items = append(items, item)
+		sb.Reset()
+		quoted = false
+	}
+
+	for i := 0; i < len(value); {
+		r, size := utf8.DecodeRuneInString(value[i:])
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				sb.WriteRune(r)
+			}
+		case o.whitespace && unicode.IsSpace(r):
+			if sb.Len() > 0 || quoted {
+				flush()
+			}
+		case !o.whitespace && strings.HasPrefix(value[i:], o.sep):
+			flush()
+			size = len(o.sep)
+		case (r == '"' || r == '\'') && !quoted && strings.TrimSpace(sb.String()) == "":
+			sb.Reset()
+			quote, quoted = r, true
+		case quoted:
+			if !unicode.IsSpace(r) {
+				return nil, fmt.Errorf("unexpected %q after quoted element", r)
+			}
+		default:
+			sb.WriteRune(r)
+		}
+		i += size
+	}
+
+	if quote != 0 {
+		return nil, errors.New("unterminated quoted element")
+	}
+	if !o.whitespace || sb.Len() > 0 || quoted {
+		flush()
+	}
+	return items, nil
+}
+
+// uniqueIf 在设置了 WithUnique 时去掉重复的元素，保留第一次出现的位置
+func uniqueIf[T comparable](items []T, o listOptions) []T {
+	if !o.unique {
+		return items
+	}
+	seen := make(map[T]bool, len(items))
+	result := items[:0]
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			result = append(result, item)
+		}
+	}
+	return result
+}
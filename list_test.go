@@ -0,0 +1,147 @@
+package ygggo_env
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetArr_Options(t *testing.T) {
+	tests := []struct {
+		name     string
+		envValue string
+		opts     []ListOption
+		expected []string
+	}{
+		{
+			name:     "semicolon separator",
+			envValue: "a;b; c",
+			opts:     []ListOption{WithSep(";")},
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:     "colon separator keeps commas",
+			envValue: "/usr/bin:/opt/a,b",
+			opts:     []ListOption{WithSep(":")},
+			expected: []string{"/usr/bin", "/opt/a,b"},
+		},
+		{
+			name:     "newline separator",
+			envValue: "a\nb\n",
+			opts:     []ListOption{WithSep("\n"), WithSkipEmpty()},
+			expected: []string{"a", "b"},
+		},
+		{
+			name:     "whitespace separator",
+			envValue: " a \t b\n\nc ",
+			opts:     []ListOption{WithWhitespace()},
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:     "quoted elements",
+			envValue: `"a,b", c ,'d, e'`,
+			opts:     []ListOption{WithQuotes()},
+			expected: []string{"a,b", "c", "d, e"},
+		},
+		{
+			name:     "empty separator keeps the default",
+			envValue: `"a,b",c`,
+			opts:     []ListOption{WithSep(""), WithQuotes()},
+			expected: []string{"a,b", "c"},
+		},
+		{
+			name:     "quotes only at element start",
+			envValue: `it's,"x"`,
+			opts:     []ListOption{WithQuotes()},
+			expected: []string{"it's", "x"},
+		},
+		{
+			name:     "quoted elements with whitespace separator",
+			envValue: `a "b c" ""`,
+			opts:     []ListOption{WithQuotes(), WithWhitespace()},
+			expected: []string{"a", "b c", ""},
+		},
+		{
+			name:     "keep empty elements by default",
+			envValue: "a,,b,",
+			expected: []string{"a", "", "b", ""},
+		},
+		{
+			name:     "skip empty elements",
+			envValue: "a,,b,",
+			opts:     []ListOption{WithSkipEmpty()},
+			expected: []string{"a", "b"},
+		},
+		{
+			name:     "unique",
+			envValue: "b,a,b,c,a",
+			opts:     []ListOption{WithUnique()},
+			expected: []string{"b", "a", "c"},
+		},
+		{
+			name:     "JSON array ignores separator",
+			envValue: `["a;b", "c"]`,
+			opts:     []ListOption{WithSep(";")},
+			expected: []string{"a;b", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_LIST", tt.envValue)
+
+			result := GetArr("TEST_LIST", nil, tt.opts...)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("GetArr() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLookupArr_QuoteErrors(t *testing.T) {
+	for _, value := range []string{`"a,b`, `"a"b,c`} {
+		t.Setenv("TEST_LIST_BAD", value)
+		if _, _, err := LookupArr("TEST_LIST_BAD", WithQuotes()); err == nil {
+			t.Errorf("LookupArr(%q) should fail", value)
+		}
+	}
+}
+
+func TestGetTypedLists(t *testing.T) {
+	t.Setenv("TEST_LIST_INTS", "80, 443,80")
+	t.Setenv("TEST_LIST_JSON_INTS", "[1, 2, 3]")
+	t.Setenv("TEST_LIST_FLOATS", "0.5;1.5")
+	t.Setenv("TEST_LIST_BOOLS", "yes off 1")
+	t.Setenv("TEST_LIST_DURATIONS", "1s,500ms")
+	t.Setenv("TEST_LIST_INVALID", "1,two,3")
+
+	if got := GetInts("TEST_LIST_INTS", nil); !reflect.DeepEqual(got, []int{80, 443, 80}) {
+		t.Errorf("GetInts() = %v", got)
+	}
+	if got := GetInts("TEST_LIST_INTS", nil, WithUnique()); !reflect.DeepEqual(got, []int{80, 443}) {
+		t.Errorf("GetInts() with WithUnique = %v", got)
+	}
+	if got := GetInts("TEST_LIST_JSON_INTS", nil); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("GetInts() from JSON = %v", got)
+	}
+	if got := GetFloats("TEST_LIST_FLOATS", nil, WithSep(";")); !reflect.DeepEqual(got, []float64{0.5, 1.5}) {
+		t.Errorf("GetFloats() = %v", got)
+	}
+	if got := GetBools("TEST_LIST_BOOLS", nil, WithWhitespace()); !reflect.DeepEqual(got, []bool{true, false, true}) {
+		t.Errorf("GetBools() = %v", got)
+	}
+	if got := GetDurations("TEST_LIST_DURATIONS", nil); !reflect.DeepEqual(got, []time.Duration{time.Second, 500 * time.Millisecond}) {
+		t.Errorf("GetDurations() = %v", got)
+	}
+
+	if got := GetInts("TEST_LIST_INVALID", []int{7}); !reflect.DeepEqual(got, []int{7}) {
+		t.Errorf("GetInts() on invalid element = %v, want default", got)
+	}
+	_, ok, err := LookupInts("TEST_LIST_INVALID")
+	if !ok || err == nil {
+		t.Fatalf("LookupInts() = %v, %v, want error", ok, err)
+	}
+	if ve, isValueError := err.(*ValueError); !isValueError || ve.Type != "[]int" {
+		t.Errorf("LookupInts() error = %v, want *ValueError for []int", err)
+	}
+}
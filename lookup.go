@@ -0,0 +1,180 @@
+package ygggo_env
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotSet 表示环境变量不存在或为空，Must 系列函数 panic 时的错误会包装它
+var ErrNotSet = errors.New("environment variable is not set")
+
+// ValueError 表示环境变量的值无法转换为期望的类型
+type ValueError struct {
+	// Key 是环境变量的名称
+	Key string
+	// Value 是环境变量的原始值
+	Value string
+	// Type 是期望的类型，例如 int、bool
+	Type string
+	// Err 是底层的转换错误
+	Err error
+}
+
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("environment variable %s=%q is not a valid %s: %v", e.Key, e.Value, e.Type, e.Err)
+}
+
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+// LookupStr 获取字符串类型的环境变量
+// 第二个返回值表示环境变量是否存在且非空；字符串不涉及转换，错误总是 nil
+func LookupStr(key string) (string, bool, error) {
+	return std.LookupStr(key)
+}
+
+// LookupStr 与包级函数 LookupStr 相同，但从当前对象的变量中读取
+func (g getter) LookupStr(key string) (string, bool, error) {
+	value := g.getenv(key)
+	return value, value != "", nil
+}
+
+// LookupInt 获取整数类型的环境变量
+// 环境变量不存在或为空时返回 ok 为 false；无法转换为整数时返回 *ValueError
+func LookupInt(key string) (int, bool, error) {
+	return std.LookupInt(key)
+}
+
+// LookupInt 与包级函数 LookupInt 相同，但从当前对象的变量中读取
+func (g getter) LookupInt(key string) (int, bool, error) {
+	return lookupValue[int](g, key)
+}
+
+// LookupFloat 获取浮点数类型的环境变量
+// 环境变量不存在或为空时返回 ok 为 false；无法转换为浮点数时返回 *ValueError
+func LookupFloat(key string) (float64, bool, error) {
+	return std.LookupFloat(key)
+}
+
+// LookupFloat 与包级函数 LookupFloat 相同，但从当前对象的变量中读取
+func (g getter) LookupFloat(key string) (float64, bool, error) {
+	return lookupValue[float64](g, key)
+}
+
+// LookupBool 获取布尔类型的环境变量，支持的写法与 GetBool 相同
+// 环境变量不存在或为空时返回 ok 为 false；无法识别为布尔值时返回 *ValueError
+func LookupBool(key string) (bool, bool, error) {
+	return std.LookupBool(key)
+}
+
+// LookupBool 与包级函数 LookupBool 相同，但从当前对象的变量中读取
+func (g getter) LookupBool(key string) (bool, bool, error) {
+	value := g.getenv(key)
+	if strings.TrimSpace(value) == "" {
+		return false, false, nil
+	}
+
+	b, err := parseBool(value)
+	if err != nil {
+		return false, true, &ValueError{Key: key, Value: value, Type: "bool", Err: err}
+	}
+	return b, true, nil
+}
+
+// LookupMap 获取字典类型的环境变量，支持的格式和选项与 GetMap 相同
+// 环境变量不存在或为空时返回 ok 为 false；无法解析时返回 *ValueError
+func LookupMap(key string, opts ...ListOption) (map[string]interface{}, bool, error) {
+	return std.LookupMap(key, opts...)
+}
+
+// LookupMap 与包级函数 LookupMap 相同，但从当前对象的变量中读取
+func (g getter) LookupMap(key string, opts ...ListOption) (map[string]interface{}, bool, error) {
+	return lookupMapOf[interface{}](g, key, opts)
+}
+
+// LookupArr 获取数组类型的环境变量，支持的格式和选项与 GetArr 相同
+// 环境变量不存在或为空时返回 ok 为 false；JSON 数组格式错误或引号未闭合时返回 *ValueError
+func LookupArr(key string, opts ...ListOption) ([]string, bool, error) {
+	return std.LookupArr(key, opts...)
+}
+
+// LookupArr 与包级函数 LookupArr 相同，但从当前对象的变量中读取
+func (g getter) LookupArr(key string, opts ...ListOption) ([]string, bool, error) {
+	return lookupList[string](g, key, opts)
+}
+
+// MustStr 获取字符串类型的环境变量，不存在或为空时 panic
+func MustStr(key string) string {
+	return std.MustStr(key)
+}
+
+// MustStr 与包级函数 MustStr 相同，但从当前对象的变量中读取
+func (g getter) MustStr(key string) string {
+	return must(g.LookupStr(key))(key)
+}
+
+// MustInt 获取整数类型的环境变量，不存在、为空或无法转换时 panic
+func MustInt(key string) int {
+	return std.MustInt(key)
+}
+
+// MustInt 与包级函数 MustInt 相同，但从当前对象的变量中读取
+func (g getter) MustInt(key string) int {
+	return must(g.LookupInt(key))(key)
+}
+
+// MustFloat 获取浮点数类型的环境变量，不存在、为空或无法转换时 panic
+func MustFloat(key string) float64 {
+	return std.MustFloat(key)
+}
+
+// MustFloat 与包级函数 MustFloat 相同，但从当前对象的变量中读取
+func (g getter) MustFloat(key string) float64 {
+	return must(g.LookupFloat(key))(key)
+}
+
+// MustBool 获取布尔类型的环境变量，不存在、为空或无法识别时 panic
+func MustBool(key string) bool {
+	return std.MustBool(key)
+}
+
+// MustBool 与包级函数 MustBool 相同，但从当前对象的变量中读取
+func (g getter) MustBool(key string) bool {
+	return must(g.LookupBool(key))(key)
+}
+
+// MustMap 获取字典类型的环境变量，不存在、为空或无法解析时 panic
+func MustMap(key string, opts ...ListOption) map[string]interface{} {
+	return std.MustMap(key, opts...)
+}
+
+// MustMap 与包级函数 MustMap 相同，但从当前对象的变量中读取
+func (g getter) MustMap(key string, opts ...ListOption) map[string]interface{} {
+	return must(g.LookupMap(key, opts...))(key)
+}
+
+// MustArr 获取数组类型的环境变量，不存在、为空或无法解析时 panic
+func MustArr(key string, opts ...ListOption) []string {
+	return std.MustArr(key, opts...)
+}
+
+// MustArr 与包级函数 MustArr 相同，但从当前对象的变量中读取
+func (g getter) MustArr(key string, opts ...ListOption) []string {
+	return must(g.LookupArr(key, opts...))(key)
+}
+
+// must 把 Lookup 系列函数的结果转换为 Must 系列的行为
+// 返回函数是为了能直接接收 Lookup 的多个返回值，再补上键名
+func must[T any](value T, ok bool, err error) func(key string) T {
+	return func(key string) T {
+		if err != nil {
+			panic(err)
+		}
+		if !ok {
+			panic(fmt.Errorf("%w: %s", ErrNotSet, key))
+		}
+		return value
+	}
+}
@@ -0,0 +1,164 @@
+package ygggo_env
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLookupInt(t *testing.T) {
+	tests := []struct {
+		name     string
+		envValue string
+		expected int
+		ok       bool
+		wantErr  bool
+	}{
+		{name: "valid", envValue: "8080", expected: 8080, ok: true},
+		{name: "negative", envValue: "-1", expected: -1, ok: true},
+		{name: "unset", envValue: "", ok: false},
+		{name: "invalid", envValue: "80a", ok: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_LOOKUP_INT", tt.envValue)
+
+			value, ok, err := LookupInt("TEST_LOOKUP_INT")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LookupInt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ok != tt.ok {
+				t.Errorf("LookupInt() ok = %v, want %v", ok, tt.ok)
+			}
+			if value != tt.expected {
+				t.Errorf("LookupInt() = %d, want %d", value, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLookup_ValueError(t *testing.T) {
+	tests := []struct {
+		name     string
+		envValue string
+		lookup   func(key string) error
+		typeName string
+	}{
+		{
+			name:     "int",
+			envValue: "80a",
+			lookup:   func(key string) error { _, _, err := LookupInt(key); return err },
+			typeName: "int",
+		},
+		{
+			name:     "float",
+			envValue: "1.2.3",
+			lookup:   func(key string) error { _, _, err := LookupFloat(key); return err },
+			typeName: "float64",
+		},
+		{
+			name:     "bool",
+			envValue: "maybe",
+			lookup:   func(key string) error { _, _, err := LookupBool(key); return err },
+			typeName: "bool",
+		},
+		{
+			name:     "map",
+			envValue: "{bad json}",
+			lookup:   func(key string) error { _, _, err := LookupMap(key); return err },
+			typeName: "map[string]interface {}",
+		},
+		{
+			name:     "array",
+			envValue: "[1, 2",
+			lookup:   func(key string) error { _, _, err := LookupArr(key); return err },
+			typeName: "[]string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_LOOKUP_VALUE", tt.envValue)
+
+			err := tt.lookup("TEST_LOOKUP_VALUE")
+			var ve *ValueError
+			if !errors.As(err, &ve) {
+				t.Fatalf("error = %v, want *ValueError", err)
+			}
+			if ve.Key != "TEST_LOOKUP_VALUE" || ve.Value != tt.envValue || ve.Type != tt.typeName {
+				t.Errorf("ValueError = %+v, want key, value %q and type %q", ve, tt.envValue, tt.typeName)
+			}
+			for _, s := range []string{"TEST_LOOKUP_VALUE", tt.envValue, tt.typeName} {
+				if !strings.Contains(err.Error(), s) {
+					t.Errorf("error %q should contain %q", err.Error(), s)
+				}
+			}
+		})
+	}
+}
+
+func TestLookup_Values(t *testing.T) {
+	t.Setenv("TEST_LOOKUP_STR", "hello")
+	t.Setenv("TEST_LOOKUP_FLOAT", "3.14")
+	t.Setenv("TEST_LOOKUP_BOOL", " Off ")
+	t.Setenv("TEST_LOOKUP_MAP", `{"port": 8080}`)
+	t.Setenv("TEST_LOOKUP_ARR", "a, b ,c")
+
+	if value, ok, err := LookupStr("TEST_LOOKUP_STR"); value != "hello" || !ok || err != nil {
+		t.Errorf("LookupStr() = %q, %v, %v", value, ok, err)
+	}
+	if value, ok, err := LookupFloat("TEST_LOOKUP_FLOAT"); value != 3.14 || !ok || err != nil {
+		t.Errorf("LookupFloat() = %v, %v, %v", value, ok, err)
+	}
+	if value, ok, err := LookupBool("TEST_LOOKUP_BOOL"); value || !ok || err != nil {
+		t.Errorf("LookupBool() = %v, %v, %v", value, ok, err)
+	}
+	if value, ok, err := LookupMap("TEST_LOOKUP_MAP"); value["port"] != float64(8080) || !ok || err != nil {
+		t.Errorf("LookupMap() = %v, %v, %v", value, ok, err)
+	}
+	if value, ok, err := LookupArr("TEST_LOOKUP_ARR"); strings.Join(value, "|") != "a|b|c" || !ok || err != nil {
+		t.Errorf("LookupArr() = %v, %v, %v", value, ok, err)
+	}
+	if _, ok, err := LookupStr("TEST_LOOKUP_UNSET"); ok || err != nil {
+		t.Errorf("LookupStr() on unset key = %v, %v", ok, err)
+	}
+}
+
+func TestMust(t *testing.T) {
+	t.Setenv("TEST_MUST_INT", "42")
+	t.Setenv("TEST_MUST_BAD", "abc")
+
+	if got := MustInt("TEST_MUST_INT"); got != 42 {
+		t.Errorf("MustInt() = %d, want 42", got)
+	}
+
+	tests := []struct {
+		name   string
+		call   func()
+		target error
+	}{
+		{name: "unset", call: func() { MustStr("TEST_MUST_UNSET") }, target: ErrNotSet},
+		{name: "invalid", call: func() { MustInt("TEST_MUST_BAD") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				err, ok := r.(error)
+				if !ok {
+					t.Fatalf("Must should panic with an error, got %v", r)
+				}
+				if tt.target != nil && !errors.Is(err, tt.target) {
+					t.Errorf("panic error = %v, want %v", err, tt.target)
+				}
+				var ve *ValueError
+				if tt.target == nil && !errors.As(err, &ve) {
+					t.Errorf("panic error = %v, want *ValueError", err)
+				}
+			}()
+			tt.call()
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package ygggo_env
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WithKVSep 使用 sep 作为字典中键和值的分隔符，默认为冒号，例如 WithKVSep("=")
+// 只用于 GetMap、GetMapOf 等字典类型的函数，sep 为空时忽略这个选项
+func WithKVSep(sep string) ListOption {
+	return func(o *listOptions) {
+		if sep == "" {
+			return
+		}
+		o.kvsep = sep
+	}
+}
+
+// WithNumber 使 GetMap 把 JSON 中的数字解析为 json.Number 而不是 float64，
+// 避免超过 2^53 的整数丢失精度
+func WithNumber() ListOption {
+	return func(o *listOptions) {
+		o.number = true
+	}
+}
+
+// GetMapOf 获取值类型为 V 的字典类型的环境变量
+// 支持两种格式：
+//  1. JSON 对象：{"a": 1, "b": 2}，值也可以是嵌套的对象或数组
+//  2. 键值对：a:1,b:2，分隔符可以通过 WithSep、WithKVSep 修改，也支持 WithQuotes 和 WithSkipEmpty
+//
+// V 可以是 Get 支持的任意类型；JSON 格式中还可以是结构体、切片和字典。
+// 如果环境变量不存在、为空或任意值无法转换为 V，返回默认值
+func GetMapOf[V any](key string, defaultValue map[string]V, opts ...ListOption) map[string]V {
+	return GetMapOfFrom(std, key, defaultValue, opts...)
+}
+
+// LookupMapOf 获取值类型为 V 的字典类型的环境变量，支持的格式与 GetMapOf 相同
+// 环境变量不存在或为空时返回 ok 为 false；无法解析时返回 *ValueError
+func LookupMapOf[V any](key string, opts ...ListOption) (map[string]V, bool, error) {
+	return LookupMapOfFrom[V](std, key, opts...)
+}
+
+// GetMapOfFrom 与 GetMapOf 相同，但从 l 中读取，例如 *Env
+func GetMapOfFrom[V any](l Lookuper, key string, defaultValue map[string]V, opts ...ListOption) map[string]V {
+	return orDefault(LookupMapOfFrom[V](l, key, opts...))(defaultValue)
+}
+
+// LookupMapOfFrom 与 LookupMapOf 相同，但从 l 中读取，例如 *Env
+func LookupMapOfFrom[V any](l Lookuper, key string, opts ...ListOption) (map[string]V, bool, error) {
+	return lookupMapOf[V](l, key, opts)
+}
+
+// lookupMapOf 是字典类型的 Lookup 函数的通用实现
+func lookupMapOf[V any](l Lookuper, key string, opts []ListOption) (map[string]V, bool, error) {
+	value := strings.TrimSpace(getenv(l, key))
+	if value == "" {
+		return nil, false, nil
+	}
+
+	result, err := parseMap[V](value, newListOptions(opts))
+	if err != nil {
+		return nil, true, &ValueError{Key: key, Value: value, Type: typeOf[map[string]V]().String(), Err: err}
+	}
+	return result, true, nil
+}
+
+// GetJSON 把 JSON 格式的环境变量解析到 dst 中，dst 必须是指针
+// 环境变量不存在或为空时返回 nil 且不修改 dst；无法解析时返回 *ValueError
+func GetJSON(key string, dst interface{}) error {
+	return std.GetJSON(key, dst)
+}
+
+// GetJSON 与包级函数 GetJSON 相同，但从当前对象的变量中读取
+func (g getter) GetJSON(key string, dst interface{}) error {
+	if rv := reflect.ValueOf(dst); rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("GetJSON requires a non-nil pointer, got %T", dst)
+	}
+
+	value := g.getenv(key)
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(value), dst); err != nil {
+		return &ValueError{Key: key, Value: value, Type: reflect.TypeOf(dst).String(), Err: err}
+	}
+	return nil
+}
+
+// parseMap 按 JSON 对象或键值对格式解析字典
+func parseMap[V any](value string, o listOptions) (map[string]V, error) {
+	result, err := parseMapValue(value, typeOf[map[string]V](), o, valueParser(o))
+	if err != nil {
+		return nil, err
+	}
+	return result.Interface().(map[string]V), nil
+}
+
+// parseMapValue 把值解析为字典类型 t，值以 { 开头时按 JSON 对象解析，否则按 o 拆分键值对
+// 值的类型是 interface{} 时，键值对中的值直接使用字符串
+func parseMapValue(value string, t reflect.Type, o listOptions, p elementParser) (reflect.Value, error) {
+	result := reflect.MakeMap(t)
+
+	if strings.HasPrefix(value, "{") {
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(value), &raw); err != nil {
+			return reflect.Value{}, err
+		}
+		for k, r := range raw {
+			mk, err := p.text(k, t.Key())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("key %q: %w", k, err)
+			}
+			mv, err := p.json(r, t.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("key %q: %w", k, err)
+			}
+			result.SetMapIndex(mk, mv)
+		}
+		return result, nil
+	}
+
+	pairs, err := splitList(value, o)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	for _, pair := range pairs {
+		k, v, ok := strings.Cut(pair, o.kvsep)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("missing %q in map entry %q", o.kvsep, pair)
+		}
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+
+		mk, err := p.text(k, t.Key())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("key %q: %w", k, err)
+		}
+		mv := reflect.ValueOf(v)
+		if t.Elem().Kind() != reflect.Interface || t.Elem().NumMethod() != 0 {
+			if mv, err = p.text(v, t.Elem()); err != nil {
+				return reflect.Value{}, fmt.Errorf("key %q: %w", k, err)
+			}
+		}
+		result.SetMapIndex(mk, mv)
+	}
+	return result, nil
+}
+
+// parseJSONElement 把 JSON 值转换为 t 类型
+// Get 支持的类型按字符串解析（JSON 字符串先去掉引号），因此 "1s" 可以转换为 time.Duration，
+// 大整数也不会经过 float64；其他类型按 JSON 解码
+func parseJSONElement(raw json.RawMessage, t reflect.Type, useNumber bool) (reflect.Value, error) {
+	if isLeaf(t) || isScalar(t) {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			s = string(raw)
+		}
+		return parseValue(s, t)
+	}
+
+	ptr := reflect.New(t)
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if useNumber {
+		dec.UseNumber()
+	}
+	if err := dec.Decode(ptr.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return ptr.Elem(), nil
+}
+
+// isScalar 判断 t 是否是 parseValue 按 Kind 处理的基本类型
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package ygggo_env
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetMap_Pairs(t *testing.T) {
+	tests := []struct {
+		name     string
+		envValue string
+		opts     []ListOption
+		expected map[string]interface{}
+	}{
+		{
+			name:     "colon pairs",
+			envValue: "host:localhost, port:3306",
+			expected: map[string]interface{}{"host": "localhost", "port": "3306"},
+		},
+		{
+			name:     "equals pairs",
+			envValue: "a=1;b=x:y",
+			opts:     []ListOption{WithSep(";"), WithKVSep("=")},
+			expected: map[string]interface{}{"a": "1", "b": "x:y"},
+		},
+		{
+			name:     "quoted pairs",
+			envValue: `"greeting:hello, world",b:2`,
+			opts:     []ListOption{WithQuotes()},
+			expected: map[string]interface{}{"greeting": "hello, world", "b": "2"},
+		},
+		{
+			name:     "JSON numbers",
+			envValue: `{"id": 9007199254740993}`,
+			opts:     []ListOption{WithNumber()},
+			expected: map[string]interface{}{"id": json.Number("9007199254740993")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_MAP", tt.envValue)
+
+			result := GetMap("TEST_MAP", nil, tt.opts...)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("GetMap() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetMapOf(t *testing.T) {
+	t.Setenv("TEST_MAP_LIMITS", "tenant-a:100,tenant-b:250")
+	t.Setenv("TEST_MAP_TIMEOUTS", `{"read": "5s", "write": "10s"}`)
+	t.Setenv("TEST_MAP_FLAGS", "beta=on;dark_mode=off")
+	t.Setenv("TEST_MAP_BIG", `{"id": 9007199254740993}`)
+	t.Setenv("TEST_MAP_NESTED", `{"a": {"x": 1}, "b": {"y": 2}}`)
+	t.Setenv("TEST_MAP_BAD", "a:1,b:two")
+
+	if got := GetMapOf[int]("TEST_MAP_LIMITS", nil); !reflect.DeepEqual(got, map[string]int{"tenant-a": 100, "tenant-b": 250}) {
+		t.Errorf("GetMapOf[int]() = %v", got)
+	}
+	if got := GetMapOf[time.Duration]("TEST_MAP_TIMEOUTS", nil); !reflect.DeepEqual(got, map[string]time.Duration{"read": 5 * time.Second, "write": 10 * time.Second}) {
+		t.Errorf("GetMapOf[time.Duration]() = %v", got)
+	}
+	if got := GetMapOf[bool]("TEST_MAP_FLAGS", nil, WithSep(";"), WithKVSep("=")); !reflect.DeepEqual(got, map[string]bool{"beta": true, "dark_mode": false}) {
+		t.Errorf("GetMapOf[bool]() = %v", got)
+	}
+	if got := GetMapOf[int64]("TEST_MAP_BIG", nil); got["id"] != 9007199254740993 {
+		t.Errorf("GetMapOf[int64]() = %v, want exact large integer", got)
+	}
+	if got := GetMapOf[map[string]int]("TEST_MAP_NESTED", nil); !reflect.DeepEqual(got, map[string]map[string]int{"a": {"x": 1}, "b": {"y": 2}}) {
+		t.Errorf("GetMapOf[map[string]int]() = %v", got)
+	}
+
+	if got := GetMapOf("TEST_MAP_BAD", map[string]int{"d": 1}); !reflect.DeepEqual(got, map[string]int{"d": 1}) {
+		t.Errorf("GetMapOf[int]() on invalid value = %v, want default", got)
+	}
+	_, ok, err := LookupMapOf[int]("TEST_MAP_BAD")
+	var ve *ValueError
+	if !ok || !errors.As(err, &ve) || ve.Type != "map[string]int" {
+		t.Errorf("LookupMapOf[int]() = %v, %v, want *ValueError", ok, err)
+	}
+}
+
+func TestGetJSON(t *testing.T) {
+	type limits struct {
+		Rate  int      `json:"rate"`
+		Burst int      `json:"burst"`
+		Paths []string `json:"paths"`
+	}
+	t.Setenv("TEST_JSON_LIMITS", `{"rate": 10, "burst": 20, "paths": ["/api"]}`)
+	t.Setenv("TEST_JSON_BAD", `{"rate": "fast"}`)
+
+	var dst limits
+	if err := GetJSON("TEST_JSON_LIMITS", &dst); err != nil {
+		t.Fatalf("GetJSON() failed: %v", err)
+	}
+	if !reflect.DeepEqual(dst, limits{Rate: 10, Burst: 20, Paths: []string{"/api"}}) {
+		t.Errorf("GetJSON() = %+v", dst)
+	}
+
+	preset := limits{Rate: 1}
+	if err := GetJSON("TEST_JSON_UNSET", &preset); err != nil || preset.Rate != 1 {
+		t.Errorf("GetJSON() on unset = %v, %+v, want nil and unchanged", err, preset)
+	}
+
+	var ve *ValueError
+	if err := GetJSON("TEST_JSON_BAD", &dst); !errors.As(err, &ve) || ve.Key != "TEST_JSON_BAD" {
+		t.Errorf("GetJSON() on invalid value = %v, want *ValueError", err)
+	}
+
+	var nilPtr *limits
+	for _, invalid := range []interface{}{nil, dst, nilPtr} {
+		if err := GetJSON("TEST_JSON_BAD", invalid); err == nil || errors.As(err, &ve) {
+			t.Errorf("GetJSON(%T) = %v, want an error about the destination", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package ygggo_env
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// GetURL 获取 URL 类型的环境变量，值必须是带 scheme 的绝对 URL
+// 传入 schemes 时 scheme 必须是其中之一 (不区分大小写)，例如 GetURL("API_URL", nil, "http", "https")
+// 如果环境变量不存在、为空或不是合法的 URL，返回默认值
+func GetURL(key string, defaultValue *url.URL, schemes ...string) *url.URL {
+	return std.GetURL(key, defaultValue, schemes...)
+}
+
+// GetURL 与包级函数 GetURL 相同，但从当前对象的变量中读取
+func (g getter) GetURL(key string, defaultValue *url.URL, schemes ...string) *url.URL {
+	return orDefault(g.LookupURL(key, schemes...))(defaultValue)
+}
+
+// GetHostPort 获取 host:port 格式的环境变量，返回主机和端口
+// 值可以只写主机 (使用 defaultPort) 或只写 :port (使用 defaultHost)，IPv6 地址写成 [::1]:8080
+// 如果环境变量不存在、为空或格式错误，返回 defaultHost 和 defaultPort
+func GetHostPort(key string, defaultHost string, defaultPort int) (string, int) {
+	return std.GetHostPort(key, defaultHost, defaultPort)
+}
+
+// GetHostPort 与包级函数 GetHostPort 相同，但从当前对象的变量中读取
+func (g getter) GetHostPort(key string, defaultHost string, defaultPort int) (string, int) {
+	host, port, ok, err := g.LookupHostPort(key, defaultPort)
+	if !ok || err != nil {
+		return defaultHost, defaultPort
+	}
+	if host == "" {
+		host = defaultHost
+	}
+	return host, port
+}
+
+// GetAddr 获取 IP 地址类型的环境变量，支持 IPv4 和 IPv6
+// 如果环境变量不存在、为空或不是合法的 IP 地址，返回默认值
+func GetAddr(key string, defaultValue netip.Addr) netip.Addr {
+	return std.GetAddr(key, defaultValue)
+}
+
+// GetAddr 与包级函数 GetAddr 相同，但从当前对象的变量中读取
+func (g getter) GetAddr(key string, defaultValue netip.Addr) netip.Addr {
+	return orDefault(g.LookupAddr(key))(defaultValue)
+}
+
+// GetPrefixes 获取 CIDR 列表类型的环境变量，例如 10.0.0.0/8,192.168.1.1
+// 不带前缀长度的地址视为单个地址 (/32 或 /128)，拆分方式与 GetArr 相同
+// 如果环境变量不存在、为空或任意元素不合法，返回默认值
+func GetPrefixes(key string, defaultValue []netip.Prefix, opts ...ListOption) []netip.Prefix {
+	return std.GetPrefixes(key, defaultValue, opts...)
+}
+
+// GetPrefixes 与包级函数 GetPrefixes 相同，但从当前对象的变量中读取
+func (g getter) GetPrefixes(key string, defaultValue []netip.Prefix, opts ...ListOption) []netip.Prefix {
+	return orDefault(g.LookupPrefixes(key, opts...))(defaultValue)
+}
+
+// LookupURL 获取 URL 类型的环境变量
+// 环境变量不存在或为空时返回 ok 为 false；不是合法的 URL 或 scheme 不允许时返回 *ValueError
+func LookupURL(key string, schemes ...string) (*url.URL, bool, error) {
+	return std.LookupURL(key, schemes...)
+}
+
+// LookupURL 与包级函数 LookupURL 相同，但从当前对象的变量中读取
+func (g getter) LookupURL(key string, schemes ...string) (*url.URL, bool, error) {
+	return lookupWith(g, key, "URL", func(value string) (*url.URL, error) {
+		u, err := url.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme == "" {
+			return nil, errors.New("missing scheme")
+		}
+		// localhost:8080 会被解析为 scheme 为 localhost 的 URL
+		if u.Opaque != "" {
+			return nil, fmt.Errorf("missing // after %s:", u.Scheme)
+		}
+		if len(schemes) > 0 && !containsFold(schemes, u.Scheme) {
+			return nil, fmt.Errorf("scheme %q is not one of %s", u.Scheme, strings.Join(schemes, ", "))
+		}
+		return u, nil
+	})
+}
+
+// LookupHostPort 获取 host:port 格式的环境变量，没有写端口时使用 defaultPort
+// 只写 :port 时返回的主机为空；环境变量不存在或为空时返回 ok 为 false；格式错误时返回 *ValueError
+func LookupHostPort(key string, defaultPort int) (string, int, bool, error) {
+	return std.LookupHostPort(key, defaultPort)
+}
+
+// LookupHostPort 与包级函数 LookupHostPort 相同，但从当前对象的变量中读取
+func (g getter) LookupHostPort(key string, defaultPort int) (string, int, bool, error) {
+	type hostPort struct {
+		host string
+		port int
+	}
+	hp, ok, err := lookupWith(g, key, "host:port", func(value string) (hostPort, error) {
+		host, port, err := splitHostPort(value, defaultPort)
+		return hostPort{host, port}, err
+	})
+	return hp.host, hp.port, ok, err
+}
+
+// LookupAddr 获取 IP 地址类型的环境变量
+// 环境变量不存在或为空时返回 ok 为 false；不是合法的 IP 地址时返回 *ValueError
+func LookupAddr(key string) (netip.Addr, bool, error) {
+	return std.LookupAddr(key)
+}
+
+// LookupAddr 与包级函数 LookupAddr 相同，但从当前对象的变量中读取
+func (g getter) LookupAddr(key string) (netip.Addr, bool, error) {
+	return lookupValue[netip.Addr](g, key)
+}
+
+// LookupPrefixes 获取 CIDR 列表类型的环境变量
+// 环境变量不存在或为空时返回 ok 为 false；任意元素不合法时返回 *ValueError
+func LookupPrefixes(key string, opts ...ListOption) ([]netip.Prefix, bool, error) {
+	return std.LookupPrefixes(key, opts...)
+}
+
+// LookupPrefixes 与包级函数 LookupPrefixes 相同，但从当前对象的变量中读取
+func (g getter) LookupPrefixes(key string, opts ...ListOption) ([]netip.Prefix, bool, error) {
+	o := newListOptions(opts)
+	return lookupWith(g, key, "[]netip.Prefix", func(value string) ([]netip.Prefix, error) {
+		items, err := splitList(value, o)
+		if err != nil {
+			return nil, err
+		}
+
+		prefixes := make([]netip.Prefix, 0, len(items))
+		for _, item := range items {
+			prefix, err := parsePrefix(item)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, prefix)
+		}
+		return uniqueIf(prefixes, o), nil
+	})
+}
+
+// MustURL 获取 URL 类型的环境变量，不存在、为空或不合法时 panic
+func MustURL(key string, schemes ...string) *url.URL {
+	return std.MustURL(key, schemes...)
+}
+
+// MustURL 与包级函数 MustURL 相同，但从当前对象的变量中读取
+func (g getter) MustURL(key string, schemes ...string) *url.URL {
+	return must(g.LookupURL(key, schemes...))(key)
+}
+
+// MustHostPort 获取 host:port 格式的环境变量，不存在、为空或格式错误时 panic
+func MustHostPort(key string, defaultPort int) (string, int) {
+	return std.MustHostPort(key, defaultPort)
+}
+
+// MustHostPort 与包级函数 MustHostPort 相同，但从当前对象的变量中读取
+func (g getter) MustHostPort(key string, defaultPort int) (string, int) {
+	host, port, ok, err := g.LookupHostPort(key, defaultPort)
+	must(host, ok, err)(key)
+	return host, port
+}
+
+// MustAddr 获取 IP 地址类型的环境变量，不存在、为空或不合法时 panic
+func MustAddr(key string) netip.Addr {
+	return std.MustAddr(key)
+}
+
+// MustAddr 与包级函数 MustAddr 相同，但从当前对象的变量中读取
+func (g getter) MustAddr(key string) netip.Addr {
+	return must(g.LookupAddr(key))(key)
+}
+
+// MustPrefixes 获取 CIDR 列表类型的环境变量，不存在、为空或任意元素不合法时 panic
+func MustPrefixes(key string, opts ...ListOption) []netip.Prefix {
+	return std.MustPrefixes(key, opts...)
+}
+
+// MustPrefixes 与包级函数 MustPrefixes 相同，但从当前对象的变量中读取
+func (g getter) MustPrefixes(key string, opts ...ListOption) []netip.Prefix {
+	return must(g.LookupPrefixes(key, opts...))(key)
+}
+
+// splitHostPort 拆分主机和端口，没有端口时使用 defaultPort
+func splitHostPort(value string, defaultPort int) (string, int, error) {
+	host, portText, err := net.SplitHostPort(value)
+	if err != nil {
+		// 没有端口的写法：主机名、IPv4，或者带或不带方括号的 IPv6
+		bare := strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+		if _, addrErr := netip.ParseAddr(bare); addrErr == nil {
+			return bare, defaultPort, nil
+		}
+		if !strings.ContainsAny(value, ":[]") {
+			return value, defaultPort, nil
+		}
+		return "", 0, err
+	}
+
+	port, err := strconv.ParseUint(portText, 10, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port %q", portText)
+	}
+	return host, int(port), nil
+}
+
+// parsePrefix 解析 CIDR，不带前缀长度的地址视为单个地址
+func parsePrefix(value string) (netip.Prefix, error) {
+	if strings.Contains(value, "/") {
+		return netip.ParsePrefix(value)
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// containsFold 判断 list 中是否有与 s 相同的元素，不区分大小写
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package ygggo_env
+
+import (
+	"net/netip"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestLookupURL(t *testing.T) {
+	tests := []struct {
+		value   string
+		schemes []string
+		wantErr bool
+	}{
+		{value: "https://api.example.com/v1"},
+		{value: "postgres://user:pass@db:5432/app", schemes: []string{"postgres", "postgresql"}},
+		{value: "HTTPS://example.com", schemes: []string{"http", "https"}},
+		{value: "file:///var/data"},
+		{value: "ftp://example.com", schemes: []string{"http", "https"}, wantErr: true},
+		{value: "example.com/path", wantErr: true},
+		{value: "localhost:8080", wantErr: true},
+		{value: "http://[::1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("TEST_URL", tt.value)
+
+			u, ok, err := LookupURL("TEST_URL", tt.schemes...)
+			if !ok || (err != nil) != tt.wantErr {
+				t.Fatalf("LookupURL() = %v, %v, %v, wantErr %v", u, ok, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetURL_Default(t *testing.T) {
+	fallback, _ := url.Parse("http://localhost")
+	t.Setenv("TEST_URL_BAD", "not a url")
+
+	if got := GetURL("TEST_URL_BAD", fallback); got != fallback {
+		t.Errorf("GetURL() on invalid value = %v, want default", got)
+	}
+	if got := GetURL("TEST_URL_UNSET", fallback); got != fallback {
+		t.Errorf("GetURL() on unset = %v, want default", got)
+	}
+}
+
+func TestGetHostPort(t *testing.T) {
+	tests := []struct {
+		value string
+		host  string
+		port  int
+	}{
+		{value: "db.local:5432", host: "db.local", port: 5432},
+		{value: "db.local", host: "db.local", port: 3306},
+		{value: ":5432", host: "localhost", port: 5432},
+		{value: "[::1]:8080", host: "::1", port: 8080},
+		{value: "[::1]", host: "::1", port: 3306},
+		{value: "2001:db8::1", host: "2001:db8::1", port: 3306},
+		{value: "db.local:99999", host: "localhost", port: 3306},
+		{value: "db.local:http", host: "localhost", port: 3306},
+		{value: "", host: "localhost", port: 3306},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("TEST_HOSTPORT", tt.value)
+
+			host, port := GetHostPort("TEST_HOSTPORT", "localhost", 3306)
+			if host != tt.host || port != tt.port {
+				t.Errorf("GetHostPort() = %s, %d, want %s, %d", host, port, tt.host, tt.port)
+			}
+		})
+	}
+
+	t.Setenv("TEST_HOSTPORT", "db.local:abc")
+	if _, _, ok, err := LookupHostPort("TEST_HOSTPORT", 80); !ok || err == nil {
+		t.Errorf("LookupHostPort() = %v, %v, want error", ok, err)
+	}
+}
+
+func TestGetAddr(t *testing.T) {
+	t.Setenv("TEST_ADDR", "192.168.1.10")
+	t.Setenv("TEST_ADDR_BAD", "192.168.1")
+
+	if got := GetAddr("TEST_ADDR", netip.Addr{}); got != netip.MustParseAddr("192.168.1.10") {
+		t.Errorf("GetAddr() = %v", got)
+	}
+	fallback := netip.MustParseAddr("127.0.0.1")
+	if got := GetAddr("TEST_ADDR_BAD", fallback); got != fallback {
+		t.Errorf("GetAddr() on invalid value = %v, want default", got)
+	}
+	if _, ok, err := LookupAddr("TEST_ADDR_BAD"); !ok || err == nil {
+		t.Errorf("LookupAddr() = %v, %v, want error", ok, err)
+	}
+}
+
+func TestGetPrefixes(t *testing.T) {
+	t.Setenv("TEST_PREFIXES", "10.0.0.0/8, 192.168.1.1 ,fd00::/8,::1")
+	t.Setenv("TEST_PREFIXES_BAD", "10.0.0.0/8,10.0.0.0/33")
+
+	expected := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.168.1.1/32"),
+		netip.MustParsePrefix("fd00::/8"),
+		netip.MustParsePrefix("::1/128"),
+	}
+	got := GetPrefixes("TEST_PREFIXES", nil)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("GetPrefixes() = %v, want %v", got, expected)
+	}
+	if !got[0].Contains(netip.MustParseAddr("10.1.2.3")) {
+		t.Errorf("prefix %v should contain 10.1.2.3", got[0])
+	}
+
+	if got := GetPrefixes("TEST_PREFIXES_BAD", nil); got != nil {
+		t.Errorf("GetPrefixes() on invalid value = %v, want default", got)
+	}
+	if _, ok, err := LookupPrefixes("TEST_PREFIXES_BAD"); !ok || err == nil {
+		t.Errorf("LookupPrefixes() = %v, %v, want error", ok, err)
+	}
+}
@@ -0,0 +1,550 @@
+package ygggo_env
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// entry 表示从 .env 文件中解析出的一个键值对
+type entry struct {
+	key    string
+	value  string
+	line   int
+	column int
+
+	// text 是记录所在行的内容，用于错误信息
+	text string
+
+	// parts 是变量展开前的值
+	parts []valuePart
+
+	// comments 是紧挨在记录上方的注释行，不含开头的 #，中间有空行时只保留空行之后的部分
+	comments []string
+}
+
+// parser 是 .env 文件的解析器
+// 语法与 docker-compose、Node dotenv 保持一致：
+//   - 单引号：原样保留，不处理转义
+//   - 双引号：支持 \n \t \r \" \\ \uXXXX 转义
+//   - 反引号：原样保留，不处理转义
+//   - 无引号：去掉首尾空白，以及空白后的 # 行内注释
+//
+// 三种引号内的值都可以跨越多行，换行会原样保留在值中；
+// 无引号的值以反斜杠结尾时与下一行拼接（同 shell 的续行）
+//
+// 双引号和无引号的值支持 $VAR、${VAR} 等变量引用（见 expand.go），
+// 使用 \$ 可以写出字面的 $
+type parser struct {
+	filename string
+	src      string
+	pos      int
+	line     int
+}
+
+// columnAt 返回偏移量所在的列号（从 1 开始，按字符计算）和该行的内容
+func columnAt(src string, pos int) (column int, text string) {
+	start := strings.LastIndexByte(src[:pos], '\n') + 1
+	end := strings.IndexByte(src[pos:], '\n')
+	if end < 0 {
+		end = len(src)
+	} else {
+		end += pos
+	}
+
+	column = utf8.RuneCountInString(src[start:pos]) + 1
+	return column, strings.TrimSpace(src[start:end])
+}
+
+// parseOptions 控制解析和变量展开的行为
+type parseOptions struct {
+	// shadow 中的值优先于文件自身的定义，例如不覆盖模式下已经存在的环境变量，
+	// 使变量引用的结果与最终生效的值一致
+	shadow func(string) (string, bool)
+	// lookup 在文件中找不到之前的定义时使用，通常是 os.LookupEnv
+	lookup func(string) (string, bool)
+	// collect 为 true 时收集所有错误并以 ParseErrors 返回，而不是在第一个错误处停止
+	collect bool
+}
+
+// parseEnv 解析 .env 文件内容，按出现顺序返回展开变量后的键值对
+// 出错时返回 *ParseError；opts.collect 为 true 时返回包含所有错误的 ParseErrors
+func parseEnv(src, filename string, opts parseOptions) ([]entry, error) {
+	entries, errs := parseRawEnv(src, filename, opts.collect)
+	if len(errs) > 0 && !opts.collect {
+		return nil, errs[0]
+	}
+
+	errs = append(errs, expandEntries(entries, filename, opts)...)
+	if len(errs) == 0 {
+		return entries, nil
+	}
+	if !opts.collect {
+		return nil, errs[0]
+	}
+	return entries, errs
+}
+
+// finalEntries 合并重复的键，同一个键以最后一次出现的值为准
+// 返回结果按键第一次出现的顺序排列
+func finalEntries(entries []entry) []entry {
+	index := make(map[string]int, len(entries))
+	var result []entry
+	for _, e := range entries {
+		if i, ok := index[e.key]; ok {
+			result[i] = e
+			continue
+		}
+		index[e.key] = len(result)
+		result = append(result, e)
+	}
+	return result
+}
+
+// parseRawEnv 只做语法解析，返回未展开变量的键值对
+// collect 为 false 时遇到第一个错误就停止；为 true 时跳过出错的行继续解析
+func parseRawEnv(src, filename string, collect bool) ([]entry, ParseErrors) {
+	p := &parser{
+		filename: filename,
+		src:      strings.ReplaceAll(strings.TrimPrefix(src, "\uFEFF"), "\r\n", "\n"),
+		line:     1,
+	}
+
+	var entries []entry
+	var errs ParseErrors
+	var comments []string
+	for {
+		line := p.line
+		p.skipBlank()
+		if p.eof() {
+			return entries, errs
+		}
+
+		// 空行把注释和后面的记录分开
+		if p.line-line > 1 {
+			comments = nil
+		}
+
+		// 注释行不产生记录，但会附加到紧随其后的记录上
+		if p.peek() == '#' {
+			start := p.pos + 1
+			p.skipLine()
+			comments = append(comments, strings.TrimSpace(p.src[start:p.pos]))
+			continue
+		}
+
+		e, err := p.parseEntry()
+		e.comments, comments = comments, nil
+		if err != nil {
+			errs = append(errs, err)
+			// 未闭合的引号会一直延伸到文件末尾，后面的内容无法可靠地解析
+			if !collect || err.Reason == ReasonUnterminatedQuote {
+				return entries, errs
+			}
+			p.skipLine()
+			continue
+		}
+		entries = append(entries, e)
+	}
+}
+
+// parseEntry 解析一条 KEY=VALUE 记录
+func (p *parser) parseEntry() (entry, *ParseError) {
+	line := p.line
+	column, lineText := columnAt(p.src, p.pos)
+
+	// 兼容 shell 风格的 export 前缀
+	if strings.HasPrefix(p.src[p.pos:], "export ") || strings.HasPrefix(p.src[p.pos:], "export\t") {
+		p.pos += len("export")
+		p.skipSpaces()
+	}
+
+	start := p.pos
+	for !p.eof() && p.peek() != '=' && !isSpace(p.peek()) && p.peek() != '\n' {
+		p.pos++
+	}
+	key := p.src[start:p.pos]
+
+	p.skipSpaces()
+	if p.eof() || p.peek() != '=' {
+		return entry{}, p.errorf(p.pos, ReasonMissingEquals, "%s", lineText)
+	}
+	if !isValidKey(key) {
+		return entry{}, p.errorf(start, ReasonInvalidKey, "invalid key name %q", key)
+	}
+	p.pos++ // 跳过 '='
+	p.skipSpaces()
+
+	parts, err := p.parseValue()
+	if err != nil {
+		return entry{}, err
+	}
+
+	return entry{key: key, line: line, column: column, text: lineText, parts: parts}, nil
+}
+
+// parseValue 根据首字符选择对应的取值规则
+func (p *parser) parseValue() ([]valuePart, *ParseError) {
+	if p.eof() {
+		return nil, nil
+	}
+
+	switch quote := p.peek(); quote {
+	case '\'', '`':
+		return p.parseRawQuoted(quote)
+	case '"':
+		return p.parseDoubleQuoted()
+	default:
+		return p.parseUnquoted()
+	}
+}
+
+// parseRawQuoted 解析单引号或反引号包裹的值，内容原样保留
+func (p *parser) parseRawQuoted(quote byte) ([]valuePart, *ParseError) {
+	open := p.pos
+	p.pos++ // 跳过起始引号
+
+	start := p.pos
+	for {
+		if p.eof() {
+			return nil, p.errorf(open, ReasonUnterminatedQuote, "unterminated quoted value")
+		}
+		if p.peek() == quote {
+			break
+		}
+		if p.peek() == '\n' {
+			p.line++
+		}
+		p.pos++
+	}
+	value := p.src[start:p.pos]
+	p.pos++ // 跳过结束引号
+
+	return []valuePart{{literal: value}}, p.finishQuoted()
+}
+
+// parseDoubleQuoted 解析双引号包裹的值，处理转义序列和变量引用
+func (p *parser) parseDoubleQuoted() ([]valuePart, *ParseError) {
+	open := p.pos
+	p.pos++ // 跳过起始引号
+
+	var b partsBuilder
+	for {
+		if p.eof() {
+			return nil, p.errorf(open, ReasonUnterminatedQuote, "unterminated quoted value")
+		}
+
+		c := p.peek()
+		if c == '"' {
+			p.pos++
+			break
+		}
+		if c == '$' {
+			if err := p.parseRef(&b, true); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if c != '\\' {
+			if c == '\n' {
+				p.line++
+			}
+			b.WriteByte(c)
+			p.pos++
+			continue
+		}
+
+		// 反斜杠加换行表示续行，不写入任何字符
+		if strings.HasPrefix(p.src[p.pos:], "\\\n") {
+			p.pos += 2
+			p.line++
+			continue
+		}
+
+		r, err := p.parseEscape()
+		if err != nil {
+			return nil, err
+		}
+		b.WriteRune(r)
+	}
+
+	return b.parts(), p.finishQuoted()
+}
+
+// parseEscape 解析以反斜杠开头的转义序列
+func (p *parser) parseEscape() (rune, *ParseError) {
+	start := p.pos
+	p.pos++ // 跳过反斜杠
+	if p.eof() {
+		return 0, p.errorf(start, ReasonUnterminatedQuote, "unterminated quoted value")
+	}
+
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'n':
+		return '\n', nil
+	case 't':
+		return '\t', nil
+	case 'r':
+		return '\r', nil
+	case '"':
+		return '"', nil
+	case '\\':
+		return '\\', nil
+	case '$':
+		return '$', nil
+	case 'u':
+		r, err := p.parseHex4(start)
+		if err != nil {
+			return 0, err
+		}
+		if r < 0xD800 || r > 0xDFFF {
+			return r, nil
+		}
+		// 代理项只能以 UTF-16 代理对的形式出现，例如 \uD83D\uDE00
+		if r < 0xDC00 && strings.HasPrefix(p.src[p.pos:], "\\u") {
+			p.pos += 2
+			low, err := p.parseHex4(start)
+			if err != nil {
+				return 0, err
+			}
+			if low >= 0xDC00 && low <= 0xDFFF {
+				return ((r - 0xD800) << 10) + (low - 0xDC00) + 0x10000, nil
+			}
+			return 0, p.errorf(start, ReasonBadEscape, "invalid unicode escape \\u%04X\\u%04X", r, low)
+		}
+		return 0, p.errorf(start, ReasonBadEscape, "invalid unicode escape \\u%04X", r)
+	default:
+		return 0, p.errorf(start, ReasonBadEscape, "invalid escape sequence \\%c", c)
+	}
+}
+
+// parseHex4 读取 \u 后面的 4 位十六进制数，start 是转义序列的起始位置
+func (p *parser) parseHex4(start int) (rune, *ParseError) {
+	if p.pos+4 > len(p.src) {
+		return 0, p.errorf(start, ReasonBadEscape, "invalid unicode escape")
+	}
+	n, err := strconv.ParseUint(p.src[p.pos:p.pos+4], 16, 32)
+	if err != nil {
+		return 0, p.errorf(start, ReasonBadEscape, "invalid unicode escape \\u%s", p.src[p.pos:p.pos+4])
+	}
+	p.pos += 4
+	return rune(n), nil
+}
+
+// parseUnquoted 解析无引号的值，去掉行内注释和首尾空白
+// 行尾的反斜杠表示续行：反斜杠和换行被移除，下一行的缩进也会被忽略
+// 其余反斜杠按字面保留（例如 Windows 路径），只有 \$ 表示字面的 $
+func (p *parser) parseUnquoted() ([]valuePart, *ParseError) {
+	var b partsBuilder
+	start := p.pos
+	for !p.eof() && p.peek() != '\n' {
+		c := p.peek()
+		switch {
+		// 只有前面是空白的 # 才是注释，a#b 中的 # 属于值的一部分
+		case c == '#' && p.pos > start && isSpace(p.src[p.pos-1]):
+			p.skipLine()
+			return b.trimmed(), nil
+		case strings.HasPrefix(p.src[p.pos:], "\\\n"):
+			p.pos += 2
+			p.line++
+			p.skipSpaces()
+		case strings.HasPrefix(p.src[p.pos:], "\\$"):
+			b.WriteByte('$')
+			p.pos += 2
+		case c == '$':
+			if err := p.parseRef(&b, false); err != nil {
+				return nil, err
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return b.trimmed(), nil
+}
+
+// parseRef 解析以 $ 开头的变量引用，不构成引用的 $ 按字面处理
+// quoted 表示引用位于双引号内，决定了默认值部分的转义规则
+func (p *parser) parseRef(b *partsBuilder, quoted bool) *ParseError {
+	start := p.pos
+	line := p.line
+	column, text := columnAt(p.src, start)
+	p.pos++ // 跳过 $
+
+	// $VAR 形式
+	if p.eof() || p.peek() != '{' {
+		name := p.scanName()
+		if name == "" {
+			b.WriteByte('$')
+			return nil
+		}
+		b.addRef(&varRef{name: name, line: line, column: column, text: text})
+		return nil
+	}
+
+	// ${VAR} 及带操作符的形式
+	p.pos++ // 跳过 {
+	name := p.scanName()
+	if name == "" {
+		return p.errorf(start, ReasonBadReference, "invalid variable reference")
+	}
+
+	ref := &varRef{name: name, line: line, column: column, text: text}
+	for _, op := range refOperators {
+		if strings.HasPrefix(p.src[p.pos:], op) {
+			ref.op = op
+			p.pos += len(op)
+			break
+		}
+	}
+	if ref.op != "" {
+		word, err := p.parseWord(quoted)
+		if err != nil {
+			return err
+		}
+		ref.word = word
+	}
+
+	if p.eof() || p.peek() != '}' {
+		return p.errorf(start, ReasonBadReference, "unterminated variable reference ${%s", name)
+	}
+	p.pos++ // 跳过 }
+
+	b.addRef(ref)
+	return nil
+}
+
+// parseWord 解析 ${VAR:-word} 中操作符后面的部分，直到对应的 }
+// 双引号内的 word 使用双引号的转义规则，无引号时只有 \$ 和 \} 是转义
+func (p *parser) parseWord(quoted bool) ([]valuePart, *ParseError) {
+	var b partsBuilder
+	for !p.eof() && p.peek() != '}' {
+		c := p.peek()
+		switch {
+		case c == '$':
+			if err := p.parseRef(&b, quoted); err != nil {
+				return nil, err
+			}
+		case c == '\\' && quoted:
+			r, err := p.parseEscape()
+			if err != nil {
+				return nil, err
+			}
+			b.WriteRune(r)
+		case c == '\\' && (strings.HasPrefix(p.src[p.pos:], "\\$") || strings.HasPrefix(p.src[p.pos:], "\\}")):
+			b.WriteByte(p.src[p.pos+1])
+			p.pos += 2
+		case c == '"' && quoted, c == '\n' && !quoted:
+			// 引号或行尾提前出现，由调用方报告未闭合的引用
+			return b.parts(), nil
+		default:
+			if c == '\n' {
+				p.line++
+			}
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return b.parts(), nil
+}
+
+// scanName 读取一个变量名：字母或下划线开头，后接字母、数字或下划线
+func (p *parser) scanName() string {
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		if c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || p.pos > start && c >= '0' && c <= '9' {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.src[start:p.pos]
+}
+
+// finishQuoted 检查引号结束后的内容，只允许空白和注释
+func (p *parser) finishQuoted() *ParseError {
+	p.skipSpaces()
+	if p.eof() || p.peek() == '\n' {
+		return nil
+	}
+	if p.peek() == '#' {
+		p.skipLine()
+		return nil
+	}
+	return p.errorf(p.pos, ReasonUnexpectedText, "unexpected characters after quoted value")
+}
+
+// errorf 生成指向 pos 位置的解析错误
+func (p *parser) errorf(pos int, reason ParseErrorReason, format string, args ...interface{}) *ParseError {
+	line := strings.Count(p.src[:pos], "\n") + 1
+	column, text := columnAt(p.src, pos)
+	return &ParseError{
+		File:   p.filename,
+		Line:   line,
+		Column: column,
+		Text:   text,
+		Reason: reason,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() byte {
+	return p.src[p.pos]
+}
+
+// skipSpaces 跳过当前行内的空格和制表符
+func (p *parser) skipSpaces() {
+	for !p.eof() && isSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+// skipBlank 跳过空白字符和空行
+func (p *parser) skipBlank() {
+	for !p.eof() {
+		switch p.peek() {
+		case '\n':
+			p.line++
+		case ' ', '\t', '\r':
+		default:
+			return
+		}
+		p.pos++
+	}
+}
+
+// skipLine 跳到当前行的行尾
+func (p *parser) skipLine() {
+	for !p.eof() && p.peek() != '\n' {
+		p.pos++
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r'
+}
+
+// isValidKey 检查键名是否合法：字母或下划线开头，后接字母、数字、下划线、点或连字符，
+// 与 Node dotenv 和 docker-compose 接受的键名一致，例如 MY-KEY
+func isValidKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, c := range key {
+		switch {
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case i > 0 && (c >= '0' && c <= '9' || c == '.' || c == '-'):
+		default:
+			return false
+		}
+	}
+	return true
+}
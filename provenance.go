@@ -0,0 +1,182 @@
+package ygggo_env
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Provenance 记录变量的一次定义：来自哪个文件的哪一行，以及是否生效
+type Provenance struct {
+	// File 是定义所在的文件，通过 Set 设置时为空
+	File string
+	// Line 是定义所在的行号，从 1 开始
+	Line int
+	// Applied 表示这个值是否写入了变量
+	Applied bool
+	// Overrode 表示写入时覆盖了之前已经存在的值，包括同一次加载中更早的定义
+	Overrode bool
+	// ShadowedBy 是使这个定义没有生效的定义；因为变量已经存在而被跳过时为 nil
+	ShadowedBy *Provenance
+}
+
+// String 返回 文件:行号 形式的位置，通过 Set 设置的值返回 Set
+func (p Provenance) String() string {
+	if p.File == "" {
+		return "Set"
+	}
+	return fmt.Sprintf("%s:%d", p.File, p.Line)
+}
+
+// provenanceRecord 是某个键的一次定义
+type provenanceRecord struct {
+	key string
+	Provenance
+}
+
+// resolveProvenance 根据一次加载的结果补全每个定义的状态
+// winner 是每个键最终写入的定义在 defs 中的下标，existed 表示写入前变量是否已经存在
+func resolveProvenance(defs []provenanceRecord, winner map[string]int, existed map[string]bool) []provenanceRecord {
+	seen := make(map[string]bool)
+	for i := range defs {
+		key := defs[i].key
+		if w, ok := winner[key]; ok && w == i {
+			defs[i].Applied = true
+			defs[i].Overrode = existed[key] || seen[key]
+		}
+		seen[key] = true
+	}
+
+	// 没有生效的定义指向最终写入的定义；键不在 winner 中说明变量已经存在，文件中的值被跳过
+	for i := range defs {
+		if w, ok := winner[defs[i].key]; ok && w != i {
+			shadowedBy := defs[w].Provenance
+			defs[i].ShadowedBy = &shadowedBy
+		}
+	}
+	return defs
+}
+
+// provenanceLog 按键保存每一次定义，可以并发使用
+type provenanceLog struct {
+	mu      sync.RWMutex
+	history map[string][]Provenance
+}
+
+func (l *provenanceLog) record(records []provenanceRecord) {
+	if len(records) == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.history == nil {
+		l.history = make(map[string][]Provenance)
+	}
+	for _, r := range records {
+		l.history[r.key] = append(l.history[r.key], r.Provenance)
+	}
+}
+
+func (l *provenanceLog) forget(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.history, key)
+}
+
+func (l *provenanceLog) get(key string) []Provenance {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]Provenance(nil), l.history[key]...)
+}
+
+// Origin 返回变量 key 当前的值来自哪个文件的哪一行
+// 变量不存在，或者当前的值不是通过加载文件或 Set 写入的（例如启动前就存在的环境变量）时，第二个返回值为 false；
+// 直接调用 os.Setenv 做的修改不会被记录
+func Origin(key string) (Provenance, bool) {
+	return std.Origin(key)
+}
+
+// Origin 与包级函数 Origin 相同，但查询 Env 中的变量
+func (e *Env) Origin(key string) (Provenance, bool) {
+	if !e.fromStore(key) {
+		return Provenance{}, false
+	}
+	history := e.provenance.get(key)
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Applied {
+			return history[i], true
+		}
+	}
+	return Provenance{}, false
+}
+
+// History 按时间顺序返回变量 key 的所有定义，包括被覆盖或被跳过的定义
+func History(key string) []Provenance {
+	return std.History(key)
+}
+
+// History 与包级函数 History 相同，但查询 Env 中的变量
+func (e *Env) History(key string) []Provenance {
+	return e.provenance.get(key)
+}
+
+// Explain 返回一段说明，描述变量 key 当前的值来自哪里，以及每一次定义是否生效，例如：
+//
+//	DB_HOST is set by .env.local:12
+//	  .env:4: shadowed by .env.local:12
+//	  .env.local:12: applied, overrode an earlier value
+//
+// 定义按时间顺序列出，说明中不包含变量的值，可以安全地写入日志
+func Explain(key string) string {
+	return std.Explain(key)
+}
+
+// Explain 与包级函数 Explain 相同，但查询 Env 中的变量
+func (e *Env) Explain(key string) string {
+	var sb strings.Builder
+	source, set := e.SourceOf(key)
+	origin, hasOrigin := e.Origin(key)
+	switch {
+	case !set:
+		fmt.Fprintf(&sb, "%s is not set", key)
+	case hasOrigin:
+		fmt.Fprintf(&sb, "%s is set by %s", key, origin)
+	case source != "":
+		fmt.Fprintf(&sb, "%s is set by %s", key, source)
+	default:
+		fmt.Fprintf(&sb, "%s is set", key)
+	}
+
+	history := e.History(key)
+	for i, p := range history {
+		fmt.Fprintf(&sb, "\n  %s: ", p)
+		switch {
+		case p.Applied:
+			sb.WriteString("applied")
+			if p.Overrode {
+				sb.WriteString(", overrode an earlier value")
+			}
+			for _, later := range history[i+1:] {
+				if later.Applied {
+					fmt.Fprintf(&sb, ", later overridden by %s", later)
+					break
+				}
+			}
+		case p.ShadowedBy != nil:
+			fmt.Fprintf(&sb, "shadowed by %s", p.ShadowedBy)
+		default:
+			sb.WriteString("skipped, the variable was already set")
+		}
+	}
+	return sb.String()
+}
+
+// fromStore 判断变量 key 当前的值是否保存在 Env 自身的存储中，而不是来自 WithSources 的来源
+func (e *Env) fromStore(key string) bool {
+	source, ok := e.SourceOf(key)
+	if !ok {
+		return false
+	}
+	_, layered := e.store.(*layeredStore)
+	return !layered || source == loadedSourceName
+}
@@ -0,0 +1,158 @@
+package ygggo_env
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEnv_Origin(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{
+		".env":       "A=base\nB=base\nC=base\nC=base2\n",
+		".env.local": "# local overrides\nB=local\n",
+	})
+	base, local := paths[".env"], paths[".env.local"]
+
+	env := New(WithVars(map[string]string{"A": "preset"}))
+	if err := env.Load(base); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if err := env.Overload(local); err != nil {
+		t.Fatalf("Overload() failed: %v", err)
+	}
+
+	tests := []struct {
+		key    string
+		origin string
+		ok     bool
+	}{
+		{"A", "", false},
+		{"B", local + ":2", true},
+		{"C", base + ":4", true},
+		{"MISSING", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			origin, ok := env.Origin(tt.key)
+			if ok != tt.ok || (ok && origin.String() != tt.origin) {
+				t.Errorf("Origin() = %v, %v, want %q, %v", origin, ok, tt.origin, tt.ok)
+			}
+		})
+	}
+
+	history := env.History("B")
+	expected := []Provenance{
+		{File: base, Line: 2, Applied: true},
+		{File: local, Line: 2, Applied: true, Overrode: true},
+	}
+	if !reflect.DeepEqual(history, expected) {
+		t.Errorf("History() = %+v, want %+v", history, expected)
+	}
+
+	history = env.History("C")
+	if len(history) != 2 || history[0].ShadowedBy == nil || history[0].ShadowedBy.Line != 4 || !history[1].Overrode {
+		t.Errorf("History() = %+v, the first C should be shadowed by the second", history)
+	}
+}
+
+func TestEnv_OriginShadowed(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{
+		".env":       "HOST=base\n",
+		".env.local": "\nHOST=local\n",
+	})
+	base, local := paths[".env"], paths[".env.local"]
+
+	env := New()
+	if err := env.Overload(base, local); err != nil {
+		t.Fatalf("Overload() failed: %v", err)
+	}
+
+	history := env.History("HOST")
+	if len(history) != 2 {
+		t.Fatalf("History() returned %d entries, want 2", len(history))
+	}
+	if history[0].Applied || history[0].ShadowedBy == nil || history[0].ShadowedBy.String() != local+":2" {
+		t.Errorf("History()[0] = %+v, want shadowed by %s:2", history[0], local)
+	}
+	if !history[1].Applied || !history[1].Overrode {
+		t.Errorf("History()[1] = %+v, want applied and overrode", history[1])
+	}
+
+	expected := "HOST is set by " + local + ":2\n" +
+		"  " + base + ":1: shadowed by " + local + ":2\n" +
+		"  " + local + ":2: applied, overrode an earlier value"
+	if got := env.Explain("HOST"); got != expected {
+		t.Errorf("Explain() = %q, want %q", got, expected)
+	}
+}
+
+func TestEnv_Explain(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{".env": "TOKEN=secret-value\n"})
+
+	env := New(WithVars(map[string]string{"TOKEN": "preset-value"}))
+	if err := env.Load(paths[".env"]); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	explained := env.Explain("TOKEN")
+	if !strings.HasPrefix(explained, "TOKEN is set by memory\n") || !strings.Contains(explained, "skipped, the variable was already set") {
+		t.Errorf("Explain() = %q", explained)
+	}
+
+	if err := env.Set("TOKEN", "new-value"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	explained = env.Explain("TOKEN")
+	if !strings.HasPrefix(explained, "TOKEN is set by Set\n") || !strings.HasSuffix(explained, "  Set: applied, overrode an earlier value") {
+		t.Errorf("Explain() after Set() = %q", explained)
+	}
+	for _, value := range []string{"secret-value", "preset-value", "new-value"} {
+		if strings.Contains(explained, value) {
+			t.Errorf("Explain() should not contain the value %q", value)
+		}
+	}
+
+	if err := env.Unset("TOKEN"); err != nil {
+		t.Fatalf("Unset() failed: %v", err)
+	}
+	if got := env.Explain("TOKEN"); got != "TOKEN is not set" {
+		t.Errorf("Explain() after Unset() = %q", got)
+	}
+}
+
+func TestEnv_OriginWithSources(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{".env": "PORT=9090\n"})
+
+	env := New(WithSources(Loaded(), Overrides(map[string]string{"HOST": "override"})))
+	if err := env.Load(paths[".env"]); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if origin, ok := env.Origin("PORT"); !ok || origin.Line != 1 {
+		t.Errorf("Origin() = %v, %v", origin, ok)
+	}
+	if _, ok := env.Origin("HOST"); ok {
+		t.Errorf("Origin() should not report values supplied by a source")
+	}
+	if got := env.Explain("HOST"); got != "HOST is set by overrides" {
+		t.Errorf("Explain() = %q", got)
+	}
+}
+
+func TestOrigin(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{".env": "TEST_ORIGIN_KEY=1\n"})
+	unsetAfter(t, "TEST_ORIGIN_KEY")
+
+	if err := Load(paths[".env"]); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if origin, ok := Origin("TEST_ORIGIN_KEY"); !ok || origin.String() != paths[".env"]+":1" {
+		t.Errorf("Origin() = %v, %v", origin, ok)
+	}
+	if history := History("TEST_ORIGIN_KEY"); len(history) == 0 {
+		t.Errorf("History() should not be empty")
+	}
+	if got := Explain("TEST_ORIGIN_KEY"); !strings.HasPrefix(got, "TEST_ORIGIN_KEY is set by "+paths[".env"]) {
+		t.Errorf("Explain() = %q", got)
+	}
+}
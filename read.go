@@ -0,0 +1,57 @@
+package ygggo_env
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Parse 使用与 LoadEnv 相同的语法解析 r 中的内容，返回键值对，不修改环境变量
+// 变量引用会查找同一内容中的定义以及当前进程的环境变量（只读）
+func Parse(r io.Reader) (map[string]string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env content: %w", err)
+	}
+
+	entries, err := parseEnv(string(content), "<input>", parseOptions{lookup: os.LookupEnv})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(entries))
+	for _, e := range entries {
+		result[e.key] = e.value
+	}
+	return result, nil
+}
+
+// Read 按顺序解析指定的文件并合并结果，不修改环境变量
+// 合并规则与 Load 相同：多个文件设置同一个键时，排在前面的文件优先；
+// 文件中的变量引用可以使用前面文件中的值以及当前进程的环境变量（只读）
+func Read(paths ...string) (map[string]string, error) {
+	return readFiles(os.ReadFile, paths)
+}
+
+// readFiles 按顺序解析多个文件并合并结果，排在前面的文件优先
+func readFiles(readFile readFileFunc, paths []string) (map[string]string, error) {
+	result := make(map[string]string)
+	shadow := func(key string) (string, bool) {
+		value, ok := result[key]
+		return value, ok
+	}
+
+	for _, path := range paths {
+		entries, err := readEnvFile(readFile, path, parseOptions{shadow: shadow, lookup: os.LookupEnv})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range finalEntries(entries) {
+			if _, exists := result[e.key]; !exists {
+				result[e.key] = e.value
+			}
+		}
+	}
+	return result, nil
+}
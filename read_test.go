@@ -0,0 +1,85 @@
+package ygggo_env
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	unsetAfter(t, "YGGGO_P_HOST", "YGGGO_P_URL")
+	t.Setenv("YGGGO_P_PORT", "3306")
+
+	input := `# database
+YGGGO_P_HOST=localhost
+YGGGO_P_URL="mysql://${YGGGO_P_HOST}:${YGGGO_P_PORT}"
+`
+	result, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"YGGGO_P_HOST": "localhost",
+		"YGGGO_P_URL":  "mysql://localhost:3306",
+	}
+	if len(result) != len(expected) {
+		t.Fatalf("Parse() returned %d keys, want %d", len(result), len(expected))
+	}
+	for key, expectedValue := range expected {
+		if result[key] != expectedValue {
+			t.Errorf("%s = %s, want %s", key, result[key], expectedValue)
+		}
+	}
+
+	// 解析不应该修改环境变量
+	for key := range expected {
+		if _, exists := os.LookupEnv(key); exists {
+			t.Errorf("Parse() should not set %s", key)
+		}
+	}
+}
+
+func TestParse_Error(t *testing.T) {
+	_, err := Parse(strings.NewReader("A=1\nINVALID"))
+	if err == nil {
+		t.Fatalf("Parse() should fail on invalid input")
+	}
+	if !strings.Contains(err.Error(), "invalid line 2") {
+		t.Errorf("Parse() error = %v, want it to mention line 2", err)
+	}
+}
+
+func TestRead(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{
+		"base.env":    "YGGGO_R_USER=app\nYGGGO_R_HOST=localhost\nYGGGO_R_DSN=${YGGGO_R_USER}@${YGGGO_R_HOST}\n",
+		"secrets.env": "YGGGO_R_USER=admin\n",
+	})
+	unsetAfter(t, "YGGGO_R_USER", "YGGGO_R_HOST", "YGGGO_R_DSN")
+
+	result, err := Read(paths["secrets.env"], paths["base.env"])
+	if err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"YGGGO_R_USER": "admin",
+		"YGGGO_R_HOST": "localhost",
+		"YGGGO_R_DSN":  "admin@localhost",
+	}
+	for key, expectedValue := range expected {
+		if result[key] != expectedValue {
+			t.Errorf("%s = %s, want %s", key, result[key], expectedValue)
+		}
+		if _, exists := os.LookupEnv(key); exists {
+			t.Errorf("Read() should not set %s", key)
+		}
+	}
+}
+
+func TestRead_MissingFile(t *testing.T) {
+	_, err := Read("/nonexistent/ygggo.env")
+	if err == nil || !strings.Contains(err.Error(), "/nonexistent/ygggo.env") {
+		t.Errorf("Read() error = %v, want it to name the missing file", err)
+	}
+}
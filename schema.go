@@ -0,0 +1,252 @@
+package ygggo_env
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SchemaFiles 是 LoadEnvChecked 查找的说明文件，排在前面的优先
+var SchemaFiles = []string{".env.example", ".env.schema"}
+
+// SchemaKey 描述说明文件中声明的一个键
+//
+// 键上方紧挨着的注释行用于描述这个键，其中以 @ 开头的是注解：
+//
+//	# 数据库端口
+//	# @type int
+//	# @optional
+//	DB_PORT=3306
+//
+// 支持的注解：
+//   - @type T      值必须能转换为类型 T，见 SchemaTypes
+//   - @required    必须设置 (默认)
+//   - @optional    可以不设置
+//   - @nonempty    设置时值不能为空
+//
+// 其他注释行合并为 Description
+type SchemaKey struct {
+	// Name 是键名
+	Name string
+	// Type 是 @type 声明的类型，为空时不检查类型
+	Type string
+	// Required 表示键必须设置，使用 @optional 时为 false
+	Required bool
+	// NonEmpty 表示键的值不能为空
+	NonEmpty bool
+	// Description 是键的说明
+	Description string
+	// Line 是键在说明文件中的行号
+	Line int
+}
+
+// Schema 是从 .env.example 或 .env.schema 中读取的键的声明
+type Schema struct {
+	// File 是说明文件的路径
+	File string
+	// Keys 按文件中出现的顺序排列
+	Keys []SchemaKey
+}
+
+// SchemaTypes 是 @type 支持的类型，值是检查函数
+var SchemaTypes = map[string]func(value string) error{
+	"string":   func(string) error { return nil },
+	"int":      checkType[int64],
+	"uint":     checkType[uint64],
+	"float":    checkType[float64],
+	"bool":     checkType[bool],
+	"duration": checkType[time.Duration],
+	"time":     checkType[time.Time],
+	"ip":       checkType[net.IP],
+	"url":      checkRuleFunc("url"),
+	"email":    checkRuleFunc("email"),
+	"hostport": checkRuleFunc("hostport"),
+	"json": func(value string) error {
+		if !json.Valid([]byte(value)) {
+			return fmt.Errorf("invalid JSON")
+		}
+		return nil
+	},
+}
+
+// ReadSchema 读取说明文件，文件使用与 .env 相同的语法
+func ReadSchema(path string) (*Schema, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open schema file %s: %w", path, err)
+	}
+	return parseSchema(string(content), path)
+}
+
+// ParseSchema 与 ReadSchema 相同，但从 r 中读取，name 用作错误中的文件名
+func ParseSchema(name string, r io.Reader) (*Schema, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema content: %w", err)
+	}
+	return parseSchema(string(content), name)
+}
+
+// parseSchema 解析说明文件的内容，示例值中的变量引用不会展开
+func parseSchema(content, filename string) (*Schema, error) {
+	entries, errs := parseRawEnv(content, filename, false)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	schema := &Schema{File: filename}
+	seen := make(map[string]int)
+	for _, e := range entries {
+		key := SchemaKey{Name: e.key, Required: true, Line: e.line}
+		var description []string
+		for _, comment := range e.comments {
+			if !strings.HasPrefix(comment, "@") {
+				if comment != "" {
+					description = append(description, comment)
+				}
+				continue
+			}
+
+			name, arg, _ := strings.Cut(comment[1:], " ")
+			arg = strings.TrimSpace(arg)
+			switch name {
+			case "type":
+				if _, ok := SchemaTypes[arg]; !ok {
+					return nil, fmt.Errorf("unknown type %q for %s in %s", arg, e.key, filename)
+				}
+				key.Type = arg
+			case "required":
+				key.Required = true
+			case "optional":
+				key.Required = false
+			case "nonempty":
+				key.NonEmpty = true
+			default:
+				return nil, fmt.Errorf("unknown annotation @%s for %s in %s", name, e.key, filename)
+			}
+		}
+		key.Description = strings.Join(description, " ")
+
+		// 重复声明时后面的生效，与 .env 文件的规则一致
+		if i, ok := seen[e.key]; ok {
+			schema.Keys[i] = key
+			continue
+		}
+		seen[e.key] = len(schema.Keys)
+		schema.Keys = append(schema.Keys, key)
+	}
+	return schema, nil
+}
+
+// Check 按声明检查当前的环境变量，loaded 是从 .env 文件中加载的键
+// 报告以下问题，没有问题时返回 nil，否则返回 Violations：
+//   - 必须设置但没有设置的键 (Rule 为 required)
+//   - 值为空但声明了 @nonempty 的键 (Rule 为 nonempty)
+//   - 值无法转换为声明的类型的键 (Rule 为 type=T)
+//   - loaded 中没有在说明文件里声明的键，通常是拼写错误 (Rule 为 declared)
+func (s *Schema) Check(loaded []string) error {
+	return s.CheckFrom(std, loaded)
+}
+
+// CheckFrom 与 Check 相同，但检查 l 中的变量，例如 *Env
+func (s *Schema) CheckFrom(l Lookuper, loaded []string) error {
+	var violations Violations
+	declared := make(map[string]bool)
+	for _, key := range s.Keys {
+		declared[key.Name] = true
+
+		value, ok := l.LookupEnv(key.Name)
+		switch {
+		case !ok:
+			if key.Required {
+				violations = append(violations, &Violation{Key: key.Name, Rule: "required", Message: "is required but not set"})
+			}
+		case value == "":
+			if key.NonEmpty {
+				violations = append(violations, &Violation{Key: key.Name, Rule: "nonempty", Message: "must not be empty"})
+			}
+		case key.Type != "":
+			if err := SchemaTypes[key.Type](value); err != nil {
+				violations = append(violations, &Violation{
+					Key:     key.Name,
+					Value:   value,
+					Rule:    "type=" + key.Type,
+					Message: fmt.Sprintf("must be a valid %s", key.Type),
+				})
+			}
+		}
+	}
+
+	var undeclared []string
+	for _, key := range loaded {
+		if !declared[key] {
+			declared[key] = true
+			undeclared = append(undeclared, key)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, key := range undeclared {
+		violations = append(violations, &Violation{
+			Key:     key,
+			Value:   getenv(l, key),
+			Rule:    "declared",
+			Message: fmt.Sprintf("is not declared in %s", s.File),
+		})
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return violations
+}
+
+// LoadEnvChecked 与 LoadEnv 相同，加载后按说明文件检查环境变量，适合在 CI 中使用
+// 说明文件是 .env 同目录下的 .env.example 或 .env.schema；
+// 没有找到 .env 时从当前目录开始向上查找说明文件，都没有找到时不做检查
+func LoadEnvChecked() error {
+	report, err := loadEnv(std, false)
+	if err != nil {
+		return err
+	}
+
+	var schemaFile string
+	if len(report.Files) > 0 {
+		opts := SearchOptions{Names: SchemaFiles, MaxDepth: 1}
+		if found := search(osSearch{}, filepath.Dir(report.Files[0]), opts); len(found) > 0 {
+			schemaFile = found[0]
+		}
+	} else if schemaFile, err = findEnvFile(SchemaFiles...); err != nil {
+		return err
+	}
+	if schemaFile == "" {
+		return nil
+	}
+
+	schema, err := ReadSchema(schemaFile)
+	if err != nil {
+		return err
+	}
+	return schema.Check(append(report.Applied, report.Skipped...))
+}
+
+// checkType 返回检查值能否转换为 T 的函数
+func checkType[T any](value string) error {
+	_, err := parseValue(value, typeOf[T]())
+	return err
+}
+
+// checkRuleFunc 把校验规则包装为检查函数
+func checkRuleFunc(rule string) func(string) error {
+	return func(value string) error {
+		if message := checkRule(nil, value, rule); message != "" {
+			return fmt.Errorf("%s", message)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,135 @@
+package ygggo_env
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSchema = `# 数据库地址
+# 格式为 host:port
+# @type hostport
+TEST_SCHEMA_DB=localhost:3306
+
+# 这段注释与下面的键之间有空行，不属于它
+
+# @type int
+# @optional
+TEST_SCHEMA_PORT=8080
+
+# @nonempty
+TEST_SCHEMA_TOKEN=
+
+TEST_SCHEMA_NAME=${USER}
+`
+
+func TestParseSchema(t *testing.T) {
+	schema, err := ParseSchema(".env.example", strings.NewReader(testSchema))
+	if err != nil {
+		t.Fatalf("ParseSchema() failed: %v", err)
+	}
+
+	expected := []SchemaKey{
+		{Name: "TEST_SCHEMA_DB", Type: "hostport", Required: true, Description: "数据库地址 格式为 host:port", Line: 4},
+		{Name: "TEST_SCHEMA_PORT", Type: "int", Required: false, Line: 10},
+		{Name: "TEST_SCHEMA_TOKEN", Required: true, NonEmpty: true, Line: 13},
+		{Name: "TEST_SCHEMA_NAME", Required: true, Line: 15},
+	}
+	if len(schema.Keys) != len(expected) {
+		t.Fatalf("ParseSchema() returned %d keys, want %d", len(schema.Keys), len(expected))
+	}
+	for i, key := range schema.Keys {
+		if key != expected[i] {
+			t.Errorf("key %d = %+v, want %+v", i, key, expected[i])
+		}
+	}
+}
+
+func TestParseSchema_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		message string
+	}{
+		{name: "unknown type", content: "# @type number\nA=1", message: `unknown type "number" for A`},
+		{name: "unknown annotation", content: "# @secret\nA=1", message: "unknown annotation @secret for A"},
+		{name: "syntax error", content: "A=1\nINVALID", message: "invalid line 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSchema(".env.example", strings.NewReader(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("ParseSchema() error = %v, want it to contain %q", err, tt.message)
+			}
+		})
+	}
+}
+
+func TestSchema_Check(t *testing.T) {
+	schema, err := ParseSchema(".env.example", strings.NewReader(testSchema))
+	if err != nil {
+		t.Fatalf("ParseSchema() failed: %v", err)
+	}
+
+	unsetAfter(t, "TEST_SCHEMA_DB", "TEST_SCHEMA_PORT", "TEST_SCHEMA_NAME")
+	t.Setenv("TEST_SCHEMA_PORT", "eighty")
+	t.Setenv("TEST_SCHEMA_TOKEN", "")
+	t.Setenv("TEST_SCHEMA_TYPO", "1")
+
+	err = schema.Check([]string{"TEST_SCHEMA_TOKEN", "TEST_SCHEMA_TYPO"})
+	var violations Violations
+	if !errors.As(err, &violations) {
+		t.Fatalf("Check() error = %v, want Violations", err)
+	}
+
+	got := make([]string, len(violations))
+	for i, v := range violations {
+		got[i] = v.Key + " " + v.Rule
+	}
+	expected := []string{
+		"TEST_SCHEMA_DB required",
+		"TEST_SCHEMA_PORT type=int",
+		"TEST_SCHEMA_TOKEN nonempty",
+		"TEST_SCHEMA_NAME required",
+		"TEST_SCHEMA_TYPO declared",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("violations = %v, want %v", got, expected)
+	}
+
+	t.Setenv("TEST_SCHEMA_DB", "db:5432")
+	t.Setenv("TEST_SCHEMA_PORT", "80")
+	t.Setenv("TEST_SCHEMA_TOKEN", "secret")
+	t.Setenv("TEST_SCHEMA_NAME", "ygg")
+	if err := schema.Check([]string{"TEST_SCHEMA_TOKEN"}); err != nil {
+		t.Errorf("Check() failed: %v", err)
+	}
+}
+
+func TestLoadEnvChecked(t *testing.T) {
+	dir := chdirTemp(t, "TEST_CHECKED_HOST=localhost\nTEST_CHECKED_PROT=80\n")
+	unsetAfter(t, "TEST_CHECKED_HOST", "TEST_CHECKED_PORT", "TEST_CHECKED_PROT")
+
+	// 没有说明文件时不做检查
+	if err := LoadEnvChecked(); err != nil {
+		t.Fatalf("LoadEnvChecked() without schema failed: %v", err)
+	}
+
+	schema := "TEST_CHECKED_HOST=\n# @type int\nTEST_CHECKED_PORT=\n"
+	if err := os.WriteFile(filepath.Join(dir, ".env.example"), []byte(schema), 0644); err != nil {
+		t.Fatalf("Failed to create .env.example: %v", err)
+	}
+
+	err := LoadEnvChecked()
+	if err == nil {
+		t.Fatalf("LoadEnvChecked() should fail")
+	}
+	for _, s := range []string{"TEST_CHECKED_PORT: is required but not set", "TEST_CHECKED_PROT: is not declared in"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("error %q should contain %q", err.Error(), s)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package ygggo_env
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// ProjectMarkers 是常见的项目根目录标记，可以用作 SearchOptions.StopAt
+var ProjectMarkers = []string{"go.mod", ".git"}
+
+// SearchOptions 控制向上查找环境变量文件的方式
+type SearchOptions struct {
+	// Dir 是开始查找的目录，为空时使用当前工作目录
+	Dir string
+	// Names 是要查找的文件名，按优先级排列，为空时查找 .env
+	Names []string
+	// Root 是查找的上界，检查完该目录后不再继续向上
+	Root string
+	// StopAt 是边界标记，例如 ProjectMarkers；
+	// 包含其中任意文件或目录的目录是最后一个被检查的目录
+	StopAt []string
+	// MaxDepth 是最多检查的目录层数（包括起始目录），0 表示不限制
+	MaxDepth int
+	// All 为 true 时收集路径上所有匹配的文件，而不是只返回第一个；
+	// 结果按离起始目录由近到远排列，同一目录内按 Names 的顺序排列
+	All bool
+}
+
+// FindEnvFiles 按 opts 从起始目录向上查找环境变量文件
+// 没有找到时返回空切片，不会报错
+func FindEnvFiles(opts SearchOptions) ([]string, error) {
+	dir := opts.Dir
+	if dir == "" {
+		currentDir, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current directory: %w", err)
+		}
+		dir = currentDir
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve directory %s: %w", opts.Dir, err)
+	}
+	if opts.Root != "" {
+		root, err := filepath.Abs(opts.Root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve root %s: %w", opts.Root, err)
+		}
+		opts.Root = root
+	}
+
+	return search(osSearch{}, dir, opts), nil
+}
+
+// LoadEnvWith 按 opts 查找并加载环境变量文件
+// 找到多个文件时（opts.All），离起始目录最近的文件优先；
+// 与 LoadEnv 相同，已经存在的环境变量不会被覆盖，没有找到文件时不报错
+func LoadEnvWith(opts SearchOptions) error {
+	paths, err := FindEnvFiles(opts)
+	if err != nil || len(paths) == 0 {
+		return err
+	}
+
+	_, err = loadFiles(std, osFiles, paths, false)
+	return err
+}
+
+// searcher 抽象了向上查找文件时需要的操作，
+// 使同一套查找逻辑既可以用于操作系统目录，也可以用于 fs.FS
+type searcher interface {
+	// isFile 判断路径是否是一个存在的普通文件
+	isFile(name string) bool
+	// exists 判断路径是否存在，可以是文件或目录
+	exists(name string) bool
+	// join 拼接目录和文件名
+	join(dir, name string) string
+	// parent 返回上一级目录，已经是根目录时返回 dir 本身
+	parent(dir string) string
+}
+
+// findUp 从 dir 开始逐级向上查找，返回最近的目录中第一个存在的文件
+// names 为空时查找 .env，没有找到时返回空字符串
+func findUp(s searcher, dir string, names []string) string {
+	found := search(s, dir, SearchOptions{Names: names})
+	if len(found) == 0 {
+		return ""
+	}
+	return found[0]
+}
+
+// search 按 opts 从 dir 开始逐级向上查找，opts.Dir 会被忽略
+func search(s searcher, dir string, opts SearchOptions) []string {
+	names := opts.Names
+	if len(names) == 0 {
+		names = []string{".env"}
+	}
+
+	var found []string
+	for depth := 1; ; depth++ {
+		for _, name := range names {
+			envPath := s.join(dir, name)
+
+			// 检查文件是否存在
+			if s.isFile(envPath) {
+				found = append(found, envPath)
+				if !opts.All {
+					return found
+				}
+			}
+		}
+
+		// 到达调用方指定的上界、项目边界或层数限制时停止
+		if dir == opts.Root || hasMarker(s, dir, opts.StopAt) {
+			return found
+		}
+		if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+			return found
+		}
+
+		// 如果已经到达根目录，停止查找
+		parentDir := s.parent(dir)
+		if parentDir == dir {
+			return found
+		}
+		dir = parentDir
+	}
+}
+
+// hasMarker 判断目录中是否包含任意一个边界标记
+func hasMarker(s searcher, dir string, markers []string) bool {
+	for _, marker := range markers {
+		if s.exists(s.join(dir, marker)) {
+			return true
+		}
+	}
+	return false
+}
+
+// osSearch 在操作系统的目录中查找
+type osSearch struct{}
+
+func (osSearch) isFile(name string) bool {
+	info, err := os.Stat(name)
+	return err == nil && !info.IsDir()
+}
+
+func (osSearch) exists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+func (osSearch) join(dir, name string) string {
+	return filepath.Join(dir, name)
+}
+
+func (osSearch) parent(dir string) string {
+	return filepath.Dir(dir)
+}
+
+// fsSearch 在 fs.FS 中查找，根目录是 "."
+type fsSearch struct {
+	fsys fs.FS
+}
+
+func (s fsSearch) isFile(name string) bool {
+	info, err := fs.Stat(s.fsys, name)
+	return err == nil && !info.IsDir()
+}
+
+func (s fsSearch) exists(name string) bool {
+	_, err := fs.Stat(s.fsys, name)
+	return err == nil
+}
+
+func (fsSearch) join(dir, name string) string {
+	return path.Join(dir, name)
+}
+
+func (fsSearch) parent(dir string) string {
+	return path.Dir(dir)
+}
@@ -0,0 +1,138 @@
+package ygggo_env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// createSearchTree 创建一个模拟 monorepo 的目录结构，返回临时根目录
+//
+//	.env                         （仓库之外的文件）
+//	repo/go.mod
+//	repo/.env
+//	repo/services/api/app.env
+//	repo/services/api/.env.development
+//	repo/services/api/cmd/
+func createSearchTree(t *testing.T) string {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	files := map[string]string{
+		".env":                               "YGGGO_S_FROM=outside\n",
+		"repo/go.mod":                        "module example.com/repo\n",
+		"repo/.env":                          "YGGGO_S_FROM=repo\nYGGGO_S_REPO=1\n",
+		"repo/services/api/app.env":          "YGGGO_S_FROM=app\n",
+		"repo/services/api/.env.development": "YGGGO_S_FROM=development\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(tempDir, "repo/services/api/cmd"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	return tempDir
+}
+
+func TestFindEnvFiles(t *testing.T) {
+	tempDir := createSearchTree(t)
+	cmdDir := filepath.Join(tempDir, "repo/services/api/cmd")
+
+	tests := []struct {
+		name     string
+		opts     SearchOptions
+		expected []string
+	}{
+		{
+			name:     "nearest .env",
+			opts:     SearchOptions{Dir: cmdDir},
+			expected: []string{"repo/.env"},
+		},
+		{
+			name:     "alternative names",
+			opts:     SearchOptions{Dir: cmdDir, Names: []string{".env.development", "app.env"}},
+			expected: []string{"repo/services/api/.env.development"},
+		},
+		{
+			name:     "stop at project boundary",
+			opts:     SearchOptions{Dir: cmdDir, Names: []string{"missing.env"}, StopAt: ProjectMarkers},
+			expected: nil,
+		},
+		{
+			name:     "collect all up to root",
+			opts:     SearchOptions{Dir: cmdDir, All: true, Root: tempDir},
+			expected: []string{"repo/.env", ".env"},
+		},
+		{
+			name:     "collect all with boundary",
+			opts:     SearchOptions{Dir: cmdDir, All: true, StopAt: ProjectMarkers},
+			expected: []string{"repo/.env"},
+		},
+		{
+			name:     "collect all names",
+			opts:     SearchOptions{Dir: cmdDir, All: true, Names: []string{"app.env", ".env"}, StopAt: ProjectMarkers},
+			expected: []string{"repo/services/api/app.env", "repo/.env"},
+		},
+		{
+			name:     "caller supplied root",
+			opts:     SearchOptions{Dir: cmdDir, Root: filepath.Join(tempDir, "repo/services")},
+			expected: nil,
+		},
+		{
+			name:     "max depth",
+			opts:     SearchOptions{Dir: cmdDir, MaxDepth: 2},
+			expected: nil,
+		},
+		{
+			name:     "max depth reaches file",
+			opts:     SearchOptions{Dir: cmdDir, MaxDepth: 4},
+			expected: []string{"repo/.env"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FindEnvFiles(tt.opts)
+			if err != nil {
+				t.Fatalf("FindEnvFiles() failed: %v", err)
+			}
+
+			if len(result) != len(tt.expected) {
+				t.Fatalf("FindEnvFiles() = %v, want %v", result, tt.expected)
+			}
+			for i, name := range tt.expected {
+				if result[i] != filepath.Join(tempDir, name) {
+					t.Errorf("FindEnvFiles()[%d] = %s, want %s", i, result[i], filepath.Join(tempDir, name))
+				}
+			}
+		})
+	}
+}
+
+func TestLoadEnvWith(t *testing.T) {
+	tempDir := createSearchTree(t)
+	unsetAfter(t, "YGGGO_S_FROM", "YGGGO_S_REPO")
+
+	err := LoadEnvWith(SearchOptions{
+		Dir:   filepath.Join(tempDir, "repo/services/api/cmd"),
+		Names: []string{"app.env", ".env"},
+		All:   true,
+	})
+	if err != nil {
+		t.Fatalf("LoadEnvWith() failed: %v", err)
+	}
+
+	// 离起始目录最近的文件优先
+	if got := os.Getenv("YGGGO_S_FROM"); got != "app" {
+		t.Errorf("YGGGO_S_FROM = %s, want app", got)
+	}
+	if got := os.Getenv("YGGGO_S_REPO"); got != "1" {
+		t.Errorf("YGGGO_S_REPO = %s, want 1", got)
+	}
+}
@@ -0,0 +1,225 @@
+package ygggo_env
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Snapshot 是某一时刻所有变量的只读副本，拥有与包级函数相同的 GetStr、LookupInt 等方法
+// Snapshot 不会改变，同一个请求从同一个 Snapshot 中读取配置，就不会看到一半新、一半旧的值：
+//
+//	cfg := gge.Current()
+//	host := cfg.GetStr("DB_HOST", "localhost")
+//	port := cfg.GetInt("DB_PORT", 3306)
+//
+// 读取不需要加锁，可以在热点路径上频繁调用
+type Snapshot struct {
+	getter
+	vars snapshotVars
+}
+
+// snapshotVars 是 Snapshot 中的变量，创建后不再修改
+type snapshotVars map[string]string
+
+func (v snapshotVars) LookupEnv(key string) (string, bool) {
+	value, ok := v[key]
+	return value, ok
+}
+
+// Environ 返回 KEY=value 格式的所有变量，按变量名排序
+func (s Snapshot) Environ() []string {
+	environ := make([]string, 0, len(s.vars))
+	for _, key := range s.keys() {
+		environ = append(environ, key+"="+s.vars[key])
+	}
+	return environ
+}
+
+func (s Snapshot) keys() []string {
+	keys := make([]string, 0, len(s.vars))
+	for key := range s.vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Current 返回当前的 Snapshot
+// 每次通过本包加载文件、重新加载或调用 Set、Unset 之后都会发布新的 Snapshot；
+// 直接调用 os.Setenv 做的修改要等到下一次发布时才会出现在 Snapshot 中
+func Current() Snapshot {
+	return std.Current()
+}
+
+// Current 与包级函数 Current 相同，返回 Env 当前的 Snapshot
+func (e *Env) Current() Snapshot {
+	if s := e.snapshots.current.Load(); s != nil {
+		return *s
+	}
+
+	// 第一次调用时才创建 Snapshot，没有使用 Snapshot 的程序不需要在每次修改后复制所有变量
+	e.loads.mu.Lock()
+	defer e.loads.mu.Unlock()
+	if s := e.snapshots.current.Load(); s != nil {
+		return *s
+	}
+	s := e.snapshot()
+	e.snapshots.current.Store(s)
+	return *s
+}
+
+// OnChange 注册发布新的 Snapshot 时调用的回调，keys 中任意变量被添加、修改或删除时才会调用，
+// keys 为空时任何变化都会调用，例如：
+//
+//	gge.OnChange([]string{"DB_HOST", "DB_PORT"}, func(old, new gge.Snapshot) {
+//		pool.Reconnect(new.GetStr("DB_HOST", ""), new.GetInt("DB_PORT", 3306))
+//	})
+//
+// 回调在释放内部的锁之后调用，因此可以在回调中读取或修改变量。
+// 回调按 Snapshot 发布的顺序依次调用，同一时刻只有一个 goroutine 在调用回调：
+// 通常是触发变化的 goroutine，如果另一个 goroutine 正在调用回调，新的变化会由它接着通知
+func OnChange(keys []string, fn func(old, new Snapshot)) {
+	std.OnChange(keys, fn)
+}
+
+// OnChange 与包级函数 OnChange 相同，订阅 Env 的变化
+func (e *Env) OnChange(keys []string, fn func(old, new Snapshot)) {
+	// 先创建当前的 Snapshot，之后的变化才能与之比较
+	e.Current()
+
+	e.snapshots.mu.Lock()
+	defer e.snapshots.mu.Unlock()
+	e.snapshots.subscribers = append(e.snapshots.subscribers, subscriber{keys: append([]string(nil), keys...), fn: fn})
+}
+
+// snapshots 保存 Env 当前的 Snapshot 和订阅者
+// pending 是按发布顺序排队、还没有通知的变化，notifying 表示是否已经有 goroutine 在通知
+type snapshots struct {
+	current atomic.Pointer[Snapshot]
+
+	mu          sync.Mutex
+	subscribers []subscriber
+	pending     []notification
+	notifying   bool
+}
+
+// notification 是一次发布的新旧两个 Snapshot
+type notification struct {
+	old, cur Snapshot
+}
+
+// subscriber 是 OnChange 注册的回调
+type subscriber struct {
+	keys []string
+	fn   func(old, new Snapshot)
+}
+
+// snapshot 复制当前所有的变量，调用者需要持有 loads.mu
+func (e *Env) snapshot() *Snapshot {
+	vars := make(snapshotVars)
+	for _, key := range e.store.keys() {
+		if value, ok := e.store.LookupEnv(key); ok {
+			vars[key] = value
+		}
+	}
+	return &Snapshot{getter: getter{src: vars}, vars: vars}
+}
+
+// update 在持有 loads.mu 的情况下执行修改，成功后发布新的 Snapshot，释放锁之后再通知订阅者
+func (e *Env) update(fn func() error) error {
+	e.loads.mu.Lock()
+	err := fn()
+	if err == nil {
+		e.publish()
+	}
+	e.loads.mu.Unlock()
+
+	e.notify()
+	return err
+}
+
+// publish 在变量发生变化时发布新的 Snapshot，并把这次变化加入待通知的队列
+// 还没有创建过 Snapshot 或者变量没有变化时什么也不做，调用者需要持有 loads.mu
+func (e *Env) publish() {
+	old := e.snapshots.current.Load()
+	if old == nil {
+		return
+	}
+	cur := e.snapshot()
+	if equalVars(old.vars, cur.vars) {
+		return
+	}
+	e.snapshots.current.Store(cur)
+
+	// 在 loads.mu 中入队，队列的顺序与发布的顺序一致
+	e.snapshots.mu.Lock()
+	e.snapshots.pending = append(e.snapshots.pending, notification{old: *old, cur: *cur})
+	e.snapshots.mu.Unlock()
+}
+
+// notify 按发布顺序通知队列中的变化，已经有 goroutine 在通知时直接返回，由它接着通知
+func (e *Env) notify() {
+	s := e.snapshots
+	s.mu.Lock()
+	if s.notifying {
+		s.mu.Unlock()
+		return
+	}
+	s.notifying = true
+
+	// 回调 panic 时也要清除 notifying，否则之后的变化不会再被通知
+	done := false
+	defer func() {
+		if !done {
+			s.mu.Lock()
+			s.notifying = false
+			s.mu.Unlock()
+		}
+	}()
+
+	for len(s.pending) > 0 {
+		n := s.pending[0]
+		s.pending = s.pending[1:]
+		subscribers := s.subscribers
+		s.mu.Unlock()
+
+		for _, sub := range subscribers {
+			if changedKeys(n.old.vars, n.cur.vars, sub.keys) {
+				sub.fn(n.old, n.cur)
+			}
+		}
+		s.mu.Lock()
+	}
+	s.notifying = false
+	done = true
+	s.mu.Unlock()
+}
+
+// equalVars 判断两组变量是否完全相同
+func equalVars(a, b snapshotVars) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
+
+// changedKeys 判断 keys 中是否有变量发生了变化，keys 为空时视为关心所有变量
+func changedKeys(old, cur snapshotVars, keys []string) bool {
+	if len(keys) == 0 {
+		return true
+	}
+	for _, key := range keys {
+		a, inOld := old[key]
+		b, inCur := cur[key]
+		if inOld != inCur || a != b {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package ygggo_env
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSnapshot_Immutable(t *testing.T) {
+	env := New(WithVars(map[string]string{"HOST": "a", "PORT": "1"}))
+
+	before := env.Current()
+	if err := env.Set("HOST", "b"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if err := env.Unset("PORT"); err != nil {
+		t.Fatalf("Unset() failed: %v", err)
+	}
+	after := env.Current()
+
+	if got := before.GetStr("HOST", ""); got != "a" {
+		t.Errorf("old snapshot HOST = %q, snapshots must not change", got)
+	}
+	if got := before.GetInt("PORT", 0); got != 1 {
+		t.Errorf("old snapshot PORT = %d, want 1", got)
+	}
+	if got := after.GetStr("HOST", ""); got != "b" {
+		t.Errorf("new snapshot HOST = %q, want b", got)
+	}
+	if _, ok := after.LookupEnv("PORT"); ok {
+		t.Errorf("new snapshot should not contain PORT")
+	}
+	if got := after.Environ(); !reflect.DeepEqual(got, []string{"HOST=b"}) {
+		t.Errorf("Environ() = %v", got)
+	}
+	if got := GetFrom(after, "HOST", ""); got != "b" {
+		t.Errorf("GetFrom() = %q, want b", got)
+	}
+}
+
+func TestSnapshot_OnChange(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{
+		"v1.env": "DB_HOST=db1\nDB_PORT=3306\nLOG_LEVEL=info\n",
+		"v2.env": "DB_HOST=db1\nDB_PORT=3306\nLOG_LEVEL=debug\n",
+		"v3.env": "DB_HOST=db2\nDB_PORT=3307\nLOG_LEVEL=debug\n",
+	})
+
+	env := New()
+	var db, all []string
+	env.OnChange([]string{"DB_HOST", "DB_PORT"}, func(old, new Snapshot) {
+		db = append(db, old.GetStr("DB_HOST", "-")+"->"+new.GetStr("DB_HOST", "-"))
+	})
+	env.OnChange(nil, func(old, new Snapshot) {
+		all = append(all, new.GetStr("LOG_LEVEL", "-"))
+	})
+
+	for _, name := range []string{"v1.env", "v2.env", "v3.env", "v3.env"} {
+		if err := env.Overload(paths[name]); err != nil {
+			t.Fatalf("Overload() failed: %v", err)
+		}
+	}
+	if err := env.Set("LOG_LEVEL", "debug"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+
+	if expected := []string{"-->db1", "db1->db2"}; !reflect.DeepEqual(db, expected) {
+		t.Errorf("DB subscriber received %v, want %v", db, expected)
+	}
+	if expected := []string{"info", "debug", "debug"}; !reflect.DeepEqual(all, expected) {
+		t.Errorf("subscriber without keys received %v, want %v", all, expected)
+	}
+}
+
+func TestSnapshot_Reload(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{".env": "FEATURE=off\n"})
+
+	env := New()
+	if err := env.Load(paths[".env"]); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	w := env.Watch(time.Hour)
+	defer w.Close()
+
+	var got []string
+	env.OnChange([]string{"FEATURE"}, func(old, new Snapshot) {
+		got = append(got, old.GetStr("FEATURE", "")+"->"+new.GetStr("FEATURE", ""))
+		// 回调中可以修改变量，不会死锁
+		env.Set("FEATURE_SEEN", "1")
+	})
+
+	rewrite(t, paths[".env"], "FEATURE=on\n")
+	if _, err := w.Check(); err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, []string{"off->on"}) {
+		t.Errorf("OnChange() received %v", got)
+	}
+	if env.Current().GetStr("FEATURE_SEEN", "") != "1" {
+		t.Errorf("Set() inside the callback should publish a new snapshot")
+	}
+}
+
+func TestSnapshot_Consistent(t *testing.T) {
+	env := New(WithVars(map[string]string{"DB_HOST": "db0", "DB_PORT": "0"}))
+	paths := make([]string, 4)
+	for i := range paths {
+		name := fmt.Sprintf("v%d.env", i)
+		paths[i] = writeEnvFiles(t, map[string]string{name: fmt.Sprintf("DB_HOST=db%d\nDB_PORT=%d\n", i, i)})[name]
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				cfg := env.Current()
+				host, port := cfg.GetStr("DB_HOST", ""), cfg.GetInt("DB_PORT", -1)
+				if host != fmt.Sprintf("db%d", port) {
+					t.Errorf("inconsistent snapshot: DB_HOST=%s DB_PORT=%d", host, port)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 200; i++ {
+		if err := env.Overload(paths[i%len(paths)]); err != nil {
+			t.Fatalf("Overload() failed: %v", err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestSnapshot_OnChangeOrder(t *testing.T) {
+	env := New(WithVars(map[string]string{}))
+
+	var mu sync.Mutex
+	last := env.Current()
+	env.OnChange([]string{"K"}, func(old, new Snapshot) {
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		if got, want := old.GetStr("K", "unset"), last.GetStr("K", "unset"); got != want {
+			t.Errorf("OnChange() old K = %s, want %s from the previous callback", got, want)
+		}
+		last = new
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := env.Set("K", fmt.Sprint(i)); err != nil {
+				t.Errorf("Set() failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(last.Environ(), env.Current().Environ()) {
+		t.Errorf("last OnChange() new = %v, want Current() %v", last.Environ(), env.Current().Environ())
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{".env": "TEST_SNAPSHOT_KEY=1\n"})
+	unsetAfter(t, "TEST_SNAPSHOT_KEY")
+
+	changed := make(chan string, 1)
+	OnChange([]string{"TEST_SNAPSHOT_KEY"}, func(old, new Snapshot) {
+		select {
+		case changed <- new.GetStr("TEST_SNAPSHOT_KEY", ""):
+		default:
+		}
+	})
+
+	if err := Load(paths[".env"]); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if got := Current().GetInt("TEST_SNAPSHOT_KEY", 0); got != 1 {
+		t.Errorf("Current().GetInt() = %d, want 1", got)
+	}
+	select {
+	case got := <-changed:
+		if got != "1" {
+			t.Errorf("OnChange() received %q, want 1", got)
+		}
+	default:
+		t.Errorf("OnChange() was not called")
+	}
+}
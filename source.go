@@ -0,0 +1,315 @@
+package ygggo_env
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// Source 是变量的一个来源，例如代码中的默认值、.env 文件、进程环境变量或命令行参数
+// 多个来源可以通过 WithSources 组合成一条查找链，Env 会报告每个变量来自哪个来源
+type Source interface {
+	Lookuper
+	// Name 返回来源的名称，用于报告变量的来源
+	Name() string
+}
+
+// keyLister 是可以列出所有变量名的来源，实现了 Keys 方法的来源会出现在 Env.Environ 和 Env.Sources 中
+type keyLister interface {
+	Keys() []string
+}
+
+// mapSource 是基于 map 的来源
+type mapSource struct {
+	name string
+	vars map[string]string
+}
+
+// MapSource 返回名为 name、变量来自 vars 的来源，vars 会被复制
+func MapSource(name string, vars map[string]string) Source {
+	s := &mapSource{name: name, vars: make(map[string]string, len(vars))}
+	for key, value := range vars {
+		s.vars[key] = value
+	}
+	return s
+}
+
+// Defaults 返回保存代码中默认值的来源，名称为 defaults
+func Defaults(vars map[string]string) Source {
+	return MapSource("defaults", vars)
+}
+
+// Overrides 返回保存程序中强制设置的值的来源，名称为 overrides
+func Overrides(vars map[string]string) Source {
+	return MapSource("overrides", vars)
+}
+
+func (s *mapSource) Name() string {
+	return s.name
+}
+
+func (s *mapSource) LookupEnv(key string) (string, bool) {
+	value, ok := s.vars[key]
+	return value, ok
+}
+
+func (s *mapSource) Keys() []string {
+	keys := make([]string, 0, len(s.vars))
+	for key := range s.vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FileSource 读取指定的文件作为来源，名称为文件路径
+// 多个文件时合并规则与 Read 相同，名称用逗号连接
+func FileSource(paths ...string) (Source, error) {
+	vars, err := Read(paths...)
+	if err != nil {
+		return nil, err
+	}
+	return MapSource(strings.Join(paths, ","), vars), nil
+}
+
+// FSSource 与 FileSource 相同，但从 fsys 中读取文件，例如通过 //go:embed 嵌入的默认配置
+// names 不传时读取根目录下的 .env，来源名称为 embed: 加上文件名
+func FSSource(fsys fs.FS, names ...string) (Source, error) {
+	if len(names) == 0 {
+		names = []string{".env"}
+	}
+	vars, err := ReadFS(fsys, names...)
+	if err != nil {
+		return nil, err
+	}
+	return MapSource("embed:"+strings.Join(names, ","), vars), nil
+}
+
+// DotEnvSource 从当前目录开始向上查找 .env 文件并读取作为来源，名称为找到的文件路径
+// 没有找到文件时返回名为 .env 的空来源，不报错
+func DotEnvSource() (Source, error) {
+	path, err := findEnvFile()
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return MapSource(".env", nil), nil
+	}
+	return FileSource(path)
+}
+
+// OSSource 返回进程环境变量作为来源，名称为 env
+func OSSource() Source {
+	return osStore{}
+}
+
+func (osStore) Name() string {
+	return "env"
+}
+
+func (s osStore) Keys() []string {
+	return s.keys()
+}
+
+// flagSource 把命令行参数作为来源
+type flagSource struct {
+	flags *flag.FlagSet
+}
+
+// FlagSource 把 flags 中在命令行上设置过的参数作为来源，名称为 flags
+// 参数名转换为大写，- 和 . 替换为 _，例如 -db-host 对应 DB_HOST；
+// 没有在命令行上设置的参数不算作已设置，因此参数的默认值不会覆盖优先级更低的来源
+// flags 为 nil 时使用 flag.CommandLine；读取发生在查找时，创建来源时参数可以尚未解析
+func FlagSource(flags *flag.FlagSet) Source {
+	if flags == nil {
+		flags = flag.CommandLine
+	}
+	return flagSource{flags: flags}
+}
+
+// flagKey 把参数名转换为变量名
+var flagKey = strings.NewReplacer("-", "_", ".", "_")
+
+func (flagSource) Name() string {
+	return "flags"
+}
+
+func (s flagSource) LookupEnv(key string) (string, bool) {
+	var value string
+	var ok bool
+	s.flags.Visit(func(f *flag.Flag) {
+		if strings.ToUpper(flagKey.Replace(f.Name)) == key {
+			value, ok = f.Value.String(), true
+		}
+	})
+	return value, ok
+}
+
+func (s flagSource) Keys() []string {
+	var keys []string
+	s.flags.Visit(func(f *flag.Flag) {
+		keys = append(keys, strings.ToUpper(flagKey.Replace(f.Name)))
+	})
+	sort.Strings(keys)
+	return keys
+}
+
+// loadedSource 是 WithSources 中保存加载的文件和 Set 写入的值的一层
+type loadedSource struct {
+	*mapStore
+}
+
+// loadedSourceName 是 Loaded 来源的名称
+const loadedSourceName = "loaded"
+
+// Loaded 返回一个用于保存 Load、Overload、LoadFS、Set 和 WithVars 写入的值的来源，名称为 loaded
+// 它在 WithSources 中的位置决定了加载的文件的优先级，例如放在 OSSource 之前时，
+// 进程环境变量优先于加载的文件，而加载的文件优先于更前面的默认值
+func Loaded() Source {
+	return loadedSource{mapStore: newMapStore(nil)}
+}
+
+func (loadedSource) Name() string {
+	return loadedSourceName
+}
+
+func (s loadedSource) Keys() []string {
+	return s.keys()
+}
+
+// errNoLoadedSource 在没有 Loaded 来源的 Env 上写入变量时返回
+var errNoLoadedSource = errors.New("env built with WithSources has no Loaded() source to write to")
+
+// layeredStore 按优先级从低到高依次排列的来源，查找时从优先级最高的来源开始
+// 写入的值保存在 loaded 指向的 Loaded 来源中，没有 Loaded 来源时 loaded 为 -1，不能写入
+type layeredStore struct {
+	sources []Source
+	loaded  int
+}
+
+// newLayeredStore 创建 layeredStore，sources 中最多只能有一个 Loaded 来源
+func newLayeredStore(sources []Source) *layeredStore {
+	s := &layeredStore{sources: sources, loaded: -1}
+	for i, src := range sources {
+		if _, ok := src.(loadedSource); ok {
+			if s.loaded >= 0 {
+				panic("ygggo_env: WithSources accepts at most one Loaded() source")
+			}
+			s.loaded = i
+		}
+	}
+	return s
+}
+
+func (s *layeredStore) LookupEnv(key string) (string, bool) {
+	value, _, ok := s.resolve(key, 0)
+	return value, ok
+}
+
+// resolve 从优先级最高的来源开始查找变量，只查找下标不小于 from 的来源，同时返回提供这个值的来源名称
+func (s *layeredStore) resolve(key string, from int) (value, source string, ok bool) {
+	for i := len(s.sources) - 1; i >= from; i-- {
+		if value, ok := s.sources[i].LookupEnv(key); ok {
+			return value, s.sources[i].Name(), true
+		}
+	}
+	return "", "", false
+}
+
+// lookupLoaded 只在 Loaded 来源及优先级更高的来源中查找变量，
+// 加载文件时用它判断变量是否已经存在，因此优先级更低的来源（例如默认值）不会阻止文件中的值写入
+func (s *layeredStore) lookupLoaded(key string) (string, bool) {
+	if s.loaded < 0 {
+		return s.LookupEnv(key)
+	}
+	value, _, ok := s.resolve(key, s.loaded)
+	return value, ok
+}
+
+// apply 把变化写入 Loaded 来源，删除变量只会删除其中的值，其他来源中的值仍然可见
+func (s *layeredStore) apply(changes []change) error {
+	if s.loaded < 0 {
+		if len(changes) == 0 {
+			return nil
+		}
+		return errNoLoadedSource
+	}
+	return s.sources[s.loaded].(loadedSource).apply(changes)
+}
+
+func (s *layeredStore) keys() []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, src := range s.sources {
+		l, ok := src.(keyLister)
+		if !ok {
+			continue
+		}
+		for _, key := range l.Keys() {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// loadLookup 返回加载文件时判断变量是否已经存在的查找函数
+func loadLookup(s store) func(string) (string, bool) {
+	if l, ok := s.(*layeredStore); ok {
+		return l.lookupLoaded
+	}
+	return s.LookupEnv
+}
+
+// WithSources 使 Env 按顺序从 sources 中查找变量，sources 按优先级从低到高排列，
+// 排在后面的来源覆盖前面的来源：
+//
+//	env := gge.New(gge.WithSources(
+//		gge.Defaults(map[string]string{"PORT": "8080"}),
+//		embedded,
+//		gge.Loaded(),
+//		gge.OSSource(),
+//		gge.FlagSource(nil),
+//		gge.Overrides(overrides),
+//	))
+//
+// Load、Set 和 WithVars 写入的值保存在 Loaded 来源中，没有 Loaded 来源时这些操作返回错误；
+// 加载文件时只有 Loaded 及排在它后面的来源中的变量被视为已经存在。
+// 使用 WithSources 时 WithOS 不生效，需要进程环境变量时请加入 OSSource
+func WithSources(sources ...Source) Option {
+	return func(o *envOptions) {
+		o.sources = append(o.sources, sources...)
+	}
+}
+
+// SourceOf 返回提供变量 key 的来源名称，变量不存在时第二个返回值为 false
+// 没有使用 WithSources 时，来源名称为 env（进程环境变量）或 memory（内存中的变量）
+func (e *Env) SourceOf(key string) (string, bool) {
+	if s, ok := e.store.(*layeredStore); ok {
+		_, source, ok := s.resolve(key, 0)
+		return source, ok
+	}
+	if _, ok := e.store.LookupEnv(key); !ok {
+		return "", false
+	}
+	if src, ok := e.store.(Source); ok {
+		return src.Name(), true
+	}
+	return "", true
+}
+
+// Sources 返回每个变量对应的来源名称，只包含能够列出变量名的来源中的变量
+func (e *Env) Sources() map[string]string {
+	result := make(map[string]string)
+	for _, key := range e.store.keys() {
+		if source, ok := e.SourceOf(key); ok {
+			result[key] = source
+		}
+	}
+	return result
+}
@@ -0,0 +1,177 @@
+package ygggo_env
+
+import (
+	"flag"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithSources_Precedence(t *testing.T) {
+	chdirTemp(t, "TEST_SRC_FILE=dotenv\nTEST_SRC_OS=dotenv\n")
+	t.Setenv("TEST_SRC_OS", "os")
+	t.Setenv("TEST_SRC_FLAG", "os")
+
+	embedded, err := FSSource(fstest.MapFS{".env": {Data: []byte("TEST_SRC_EMBED=embed\nTEST_SRC_FILE=embed\n")}})
+	if err != nil {
+		t.Fatalf("FSSource() failed: %v", err)
+	}
+	dotenv, err := DotEnvSource()
+	if err != nil {
+		t.Fatalf("DotEnvSource() failed: %v", err)
+	}
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.String("test-src-flag", "", "")
+	flags.String("test-src.unset", "default", "")
+	flags.String("test-src-override", "", "")
+	if err := flags.Parse([]string{"-test-src-flag=flag", "-test-src-override=flag"}); err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	env := New(WithSources(
+		Defaults(map[string]string{"TEST_SRC_DEFAULT": "default", "TEST_SRC_EMBED": "default"}),
+		embedded,
+		dotenv,
+		OSSource(),
+		FlagSource(flags),
+		Overrides(map[string]string{"TEST_SRC_OVERRIDE": "override"}),
+	))
+
+	tests := []struct {
+		key    string
+		value  string
+		source string
+	}{
+		{"TEST_SRC_DEFAULT", "default", "defaults"},
+		{"TEST_SRC_EMBED", "embed", "embed:.env"},
+		{"TEST_SRC_FILE", "dotenv", ".env"},
+		{"TEST_SRC_OS", "os", "env"},
+		{"TEST_SRC_FLAG", "flag", "flags"},
+		{"TEST_SRC_OVERRIDE", "override", "overrides"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := env.GetStr(tt.key, ""); got != tt.value {
+				t.Errorf("GetStr() = %q, want %q", got, tt.value)
+			}
+			source, ok := env.SourceOf(tt.key)
+			if !ok || filepath.Base(source) != tt.source {
+				t.Errorf("SourceOf() = %q, %v, want %q", source, ok, tt.source)
+			}
+		})
+	}
+
+	if _, ok := env.LookupEnv("TEST_SRC_UNSET"); ok {
+		t.Errorf("flags that were not set should not be visible")
+	}
+	if _, ok := env.SourceOf("TEST_SRC_MISSING"); ok {
+		t.Errorf("SourceOf() should report missing keys")
+	}
+}
+
+func TestWithSources_Set(t *testing.T) {
+	env := New(WithSources(
+		Defaults(map[string]string{"PORT": "8080", "HOST": "localhost"}),
+		Loaded(),
+		Overrides(map[string]string{"HOST": "override"}),
+	))
+
+	if err := env.Set("PORT", "9090"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if source, _ := env.SourceOf("PORT"); source != "loaded" || env.GetInt("PORT", 0) != 9090 {
+		t.Errorf("Set() value should win over lower sources, got source %q", source)
+	}
+	if err := env.Set("HOST", "set"); err != nil {
+		t.Fatalf("Set() failed: %v", err)
+	}
+	if source, _ := env.SourceOf("HOST"); source != "overrides" || env.GetStr("HOST", "") != "override" {
+		t.Errorf("Overrides should win over Set(), got source %q", source)
+	}
+
+	if err := env.Unset("PORT"); err != nil {
+		t.Fatalf("Unset() failed: %v", err)
+	}
+	if source, _ := env.SourceOf("PORT"); source != "defaults" || env.GetInt("PORT", 0) != 8080 {
+		t.Errorf("Unset() should fall back to the sources, got source %q", source)
+	}
+}
+
+func TestWithSources_Load(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{".env": "PORT=9090\nHOST=file\nLEVEL=file\n"})
+
+	env := New(WithSources(
+		Defaults(map[string]string{"PORT": "8080"}),
+		Loaded(),
+		MapSource("env", map[string]string{"LEVEL": "os"}),
+		Overrides(map[string]string{"HOST": "override"}),
+	))
+	if err := env.Load(paths[".env"]); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	tests := []struct {
+		key    string
+		value  string
+		source string
+	}{
+		{"PORT", "9090", "loaded"},
+		{"HOST", "override", "overrides"},
+		{"LEVEL", "os", "env"},
+	}
+	for _, tt := range tests {
+		if got := env.GetStr(tt.key, ""); got != tt.value {
+			t.Errorf("GetStr(%s) = %q, want %q", tt.key, got, tt.value)
+		}
+		if source, _ := env.SourceOf(tt.key); source != tt.source {
+			t.Errorf("SourceOf(%s) = %q, want %q", tt.key, source, tt.source)
+		}
+	}
+
+	readOnly := New(WithSources(Defaults(map[string]string{"PORT": "8080"})))
+	if err := readOnly.Load(paths[".env"]); err == nil {
+		t.Errorf("Load() without a Loaded() source should fail")
+	}
+	if err := readOnly.Set("PORT", "1"); err == nil {
+		t.Errorf("Set() without a Loaded() source should fail")
+	}
+	if got := readOnly.GetInt("PORT", 0); got != 8080 {
+		t.Errorf("failed Load() should not change anything, PORT = %d", got)
+	}
+}
+
+func TestEnv_Sources(t *testing.T) {
+	env := New(
+		WithVars(map[string]string{"A": "1"}),
+		WithSources(Defaults(map[string]string{"A": "0", "B": "2"}), Loaded()),
+	)
+
+	expected := map[string]string{"A": "loaded", "B": "defaults"}
+	if got := env.Sources(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Sources() = %v, want %v", got, expected)
+	}
+	if got := env.Environ(); !reflect.DeepEqual(got, []string{"A=1", "B=2"}) {
+		t.Errorf("Environ() = %v", got)
+	}
+
+	if source, _ := New(WithVars(map[string]string{"A": "1"})).SourceOf("A"); source != "memory" {
+		t.Errorf("SourceOf() = %q, want memory", source)
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{"a.env": "A=1\n", "b.env": "A=2\nB=2\n"})
+
+	src, err := FileSource(paths["a.env"], paths["b.env"])
+	if err != nil {
+		t.Fatalf("FileSource() failed: %v", err)
+	}
+	if value, _ := src.LookupEnv("A"); value != "1" {
+		t.Errorf("FileSource() A = %q, earlier files should win", value)
+	}
+	if _, err := FileSource(paths["a.env"] + ".missing"); err == nil {
+		t.Errorf("FileSource() should fail for a missing file")
+	}
+}
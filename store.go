@@ -0,0 +1,116 @@
+package ygggo_env
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Lookuper 是可以按名称查找环境变量的对象，*Env 实现了这个接口
+// 第二个返回值表示变量是否存在，语义与 os.LookupEnv 相同
+type Lookuper interface {
+	LookupEnv(key string) (string, bool)
+}
+
+// store 是 Env 保存变量的地方
+type store interface {
+	Lookuper
+	// apply 按顺序写入一组变化
+	apply(changes []change) error
+	// keys 返回所有变量名，按字母顺序排列
+	keys() []string
+}
+
+// change 是对一个变量的修改
+type change struct {
+	key   string
+	value string
+	// unset 为 true 时删除变量，忽略 value
+	unset bool
+}
+
+// osStore 把变量保存在进程环境变量中
+type osStore struct{}
+
+func (osStore) LookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+func (osStore) apply(changes []change) error {
+	for _, c := range changes {
+		if c.unset {
+			if err := os.Unsetenv(c.key); err != nil {
+				return fmt.Errorf("failed to unset environment variable %s: %w", c.key, err)
+			}
+			continue
+		}
+		if err := os.Setenv(c.key, c.value); err != nil {
+			return fmt.Errorf("failed to set environment variable %s: %w", c.key, err)
+		}
+	}
+	return nil
+}
+
+func (osStore) keys() []string {
+	var keys []string
+	for _, kv := range os.Environ() {
+		if key, _, ok := strings.Cut(kv, "="); ok && key != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mapStore 把变量保存在内存中，可以并发使用
+// 一次 apply 中的所有变化在同一把锁内写入，读取者不会看到只写入了一部分的状态
+type mapStore struct {
+	mu   sync.RWMutex
+	vars map[string]string
+}
+
+func newMapStore(vars map[string]string) *mapStore {
+	s := &mapStore{vars: make(map[string]string, len(vars))}
+	for key, value := range vars {
+		s.vars[key] = value
+	}
+	return s
+}
+
+func (s *mapStore) Name() string {
+	return "memory"
+}
+
+func (s *mapStore) LookupEnv(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.vars[key]
+	return value, ok
+}
+
+func (s *mapStore) apply(changes []change) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range changes {
+		if c.unset {
+			delete(s.vars, c.key)
+		} else {
+			s.vars[c.key] = c.value
+		}
+	}
+	return nil
+}
+
+func (s *mapStore) keys() []string {
+	s.mu.RLock()
+	keys := make([]string, 0, len(s.vars))
+	for key := range s.vars {
+		keys = append(keys, key)
+	}
+	s.mu.RUnlock()
+
+	sort.Strings(keys)
+	return keys
+}
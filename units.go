@@ -0,0 +1,266 @@
+package ygggo_env
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sizeUnits 是 GetSize 支持的单位，键为小写
+// 带 B 的十进制单位按 1000 进位，带 iB 的单位和只写一个字母的单位按 1024 进位
+var sizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1 << 10,
+	"m":   1 << 20,
+	"g":   1 << 30,
+	"t":   1 << 40,
+	"p":   1 << 50,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"pb":  1e15,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+// GetDuration 获取时长类型的环境变量，写法与 time.ParseDuration 相同，例如 30s、1h30m
+// 如果环境变量不存在、为空或无法转换为时长，返回默认值
+func GetDuration(key string, defaultValue time.Duration) time.Duration {
+	return std.GetDuration(key, defaultValue)
+}
+
+// GetDuration 与包级函数 GetDuration 相同，但从当前对象的变量中读取
+func (g getter) GetDuration(key string, defaultValue time.Duration) time.Duration {
+	return orDefault(g.LookupDuration(key))(defaultValue)
+}
+
+// GetDurationIn 与 GetDuration 相同，但允许不带单位的数字，按 unit 计算
+// 例如 GetDurationIn("TIMEOUT", 5*time.Second, time.Millisecond) 把 TIMEOUT=250 解析为 250ms
+func GetDurationIn(key string, defaultValue time.Duration, unit time.Duration) time.Duration {
+	return std.GetDurationIn(key, defaultValue, unit)
+}
+
+// GetDurationIn 与包级函数 GetDurationIn 相同，但从当前对象的变量中读取
+func (g getter) GetDurationIn(key string, defaultValue time.Duration, unit time.Duration) time.Duration {
+	return orDefault(g.LookupDurationIn(key, unit))(defaultValue)
+}
+
+// GetSize 获取字节数类型的环境变量，返回字节数
+// 支持 512、512B、64k、1.5GiB、100MB 等写法，单位不区分大小写：
+//   - KB、MB、GB、TB、PB 按 1000 进位
+//   - KiB、MiB、GiB、TiB、PiB 按 1024 进位
+//   - K、M、G、T、P 按 1024 进位，与 Docker、JVM 等工具的习惯一致
+//
+// 如果环境变量不存在、为空或无法识别，返回默认值
+func GetSize(key string, defaultValue int64) int64 {
+	return std.GetSize(key, defaultValue)
+}
+
+// GetSize 与包级函数 GetSize 相同，但从当前对象的变量中读取
+func (g getter) GetSize(key string, defaultValue int64) int64 {
+	return orDefault(g.LookupSize(key))(defaultValue)
+}
+
+// GetTime 获取时间类型的环境变量，依次尝试 layouts 中的格式，不传时使用 time.RFC3339
+// 如果环境变量不存在、为空或与所有格式都不匹配，返回默认值
+func GetTime(key string, defaultValue time.Time, layouts ...string) time.Time {
+	return std.GetTime(key, defaultValue, layouts...)
+}
+
+// GetTime 与包级函数 GetTime 相同，但从当前对象的变量中读取
+func (g getter) GetTime(key string, defaultValue time.Time, layouts ...string) time.Time {
+	return orDefault(g.LookupTime(key, layouts...))(defaultValue)
+}
+
+// GetLocation 获取时区类型的环境变量，值是 IANA 时区名，例如 Asia/Shanghai、UTC、Local
+// 如果环境变量不存在、为空或时区不存在，返回默认值
+func GetLocation(key string, defaultValue *time.Location) *time.Location {
+	return std.GetLocation(key, defaultValue)
+}
+
+// GetLocation 与包级函数 GetLocation 相同，但从当前对象的变量中读取
+func (g getter) GetLocation(key string, defaultValue *time.Location) *time.Location {
+	return orDefault(g.LookupLocation(key))(defaultValue)
+}
+
+// LookupDuration 获取时长类型的环境变量
+// 环境变量不存在或为空时返回 ok 为 false；无法转换时返回 *ValueError
+func LookupDuration(key string) (time.Duration, bool, error) {
+	return std.LookupDuration(key)
+}
+
+// LookupDuration 与包级函数 LookupDuration 相同，但从当前对象的变量中读取
+func (g getter) LookupDuration(key string) (time.Duration, bool, error) {
+	return lookupValue[time.Duration](g, key)
+}
+
+// LookupDurationIn 获取时长类型的环境变量，不带单位的数字按 unit 计算
+// 环境变量不存在或为空时返回 ok 为 false；无法转换时返回 *ValueError
+func LookupDurationIn(key string, unit time.Duration) (time.Duration, bool, error) {
+	return std.LookupDurationIn(key, unit)
+}
+
+// LookupDurationIn 与包级函数 LookupDurationIn 相同，但从当前对象的变量中读取
+func (g getter) LookupDurationIn(key string, unit time.Duration) (time.Duration, bool, error) {
+	return lookupWith(g, key, "duration", func(value string) (time.Duration, error) {
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			d := n * float64(unit)
+			if math.IsNaN(d) || math.IsInf(d, 0) {
+				return 0, errors.New("duration is not a finite number")
+			}
+			if math.Abs(d) >= math.MaxInt64 {
+				return 0, errors.New("duration out of range")
+			}
+			return time.Duration(d), nil
+		}
+		return time.ParseDuration(value)
+	})
+}
+
+// LookupSize 获取字节数类型的环境变量，支持的写法与 GetSize 相同
+// 环境变量不存在或为空时返回 ok 为 false；无法识别时返回 *ValueError
+func LookupSize(key string) (int64, bool, error) {
+	return std.LookupSize(key)
+}
+
+// LookupSize 与包级函数 LookupSize 相同，但从当前对象的变量中读取
+func (g getter) LookupSize(key string) (int64, bool, error) {
+	return lookupWith(g, key, "size", parseSize)
+}
+
+// LookupTime 获取时间类型的环境变量，依次尝试 layouts 中的格式，不传时使用 time.RFC3339
+// 环境变量不存在或为空时返回 ok 为 false；与所有格式都不匹配时返回 *ValueError
+func LookupTime(key string, layouts ...string) (time.Time, bool, error) {
+	return std.LookupTime(key, layouts...)
+}
+
+// LookupTime 与包级函数 LookupTime 相同，但从当前对象的变量中读取
+func (g getter) LookupTime(key string, layouts ...string) (time.Time, bool, error) {
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339}
+	}
+	return lookupWith(g, key, "time", func(value string) (time.Time, error) {
+		var firstErr error
+		for _, layout := range layouts {
+			t, err := time.Parse(layout, value)
+			if err == nil {
+				return t, nil
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		return time.Time{}, firstErr
+	})
+}
+
+// LookupLocation 获取时区类型的环境变量
+// 环境变量不存在或为空时返回 ok 为 false；时区不存在时返回 *ValueError
+func LookupLocation(key string) (*time.Location, bool, error) {
+	return std.LookupLocation(key)
+}
+
+// LookupLocation 与包级函数 LookupLocation 相同，但从当前对象的变量中读取
+func (g getter) LookupLocation(key string) (*time.Location, bool, error) {
+	return lookupWith(g, key, "location", time.LoadLocation)
+}
+
+// MustDuration 获取时长类型的环境变量，不存在、为空或无法转换时 panic
+func MustDuration(key string) time.Duration {
+	return std.MustDuration(key)
+}
+
+// MustDuration 与包级函数 MustDuration 相同，但从当前对象的变量中读取
+func (g getter) MustDuration(key string) time.Duration {
+	return must(g.LookupDuration(key))(key)
+}
+
+// MustDurationIn 获取时长类型的环境变量，不带单位的数字按 unit 计算，不存在、为空或无法转换时 panic
+func MustDurationIn(key string, unit time.Duration) time.Duration {
+	return std.MustDurationIn(key, unit)
+}
+
+// MustDurationIn 与包级函数 MustDurationIn 相同，但从当前对象的变量中读取
+func (g getter) MustDurationIn(key string, unit time.Duration) time.Duration {
+	return must(g.LookupDurationIn(key, unit))(key)
+}
+
+// MustSize 获取字节数类型的环境变量，不存在、为空或无法识别时 panic
+func MustSize(key string) int64 {
+	return std.MustSize(key)
+}
+
+// MustSize 与包级函数 MustSize 相同，但从当前对象的变量中读取
+func (g getter) MustSize(key string) int64 {
+	return must(g.LookupSize(key))(key)
+}
+
+// MustTime 获取时间类型的环境变量，不存在、为空或与所有格式都不匹配时 panic
+func MustTime(key string, layouts ...string) time.Time {
+	return std.MustTime(key, layouts...)
+}
+
+// MustTime 与包级函数 MustTime 相同，但从当前对象的变量中读取
+func (g getter) MustTime(key string, layouts ...string) time.Time {
+	return must(g.LookupTime(key, layouts...))(key)
+}
+
+// MustLocation 获取时区类型的环境变量，不存在、为空或时区不存在时 panic
+func MustLocation(key string) *time.Location {
+	return std.MustLocation(key)
+}
+
+// MustLocation 与包级函数 MustLocation 相同，但从当前对象的变量中读取
+func (g getter) MustLocation(key string) *time.Location {
+	return must(g.LookupLocation(key))(key)
+}
+
+// lookupWith 使用 parse 转换环境变量的值，typeName 用于错误信息
+func lookupWith[T any](l Lookuper, key, typeName string, parse func(string) (T, error)) (T, bool, error) {
+	var zero T
+	value := getenv(l, key)
+	if strings.TrimSpace(value) == "" {
+		return zero, false, nil
+	}
+
+	result, err := parse(strings.TrimSpace(value))
+	if err != nil {
+		return zero, true, &ValueError{Key: key, Value: value, Type: typeName, Err: err}
+	}
+	return result, true, nil
+}
+
+// parseSize 把 1.5GiB 这样的写法转换为字节数
+func parseSize(value string) (int64, error) {
+	i := 0
+	for i < len(value) && (value[i] >= '0' && value[i] <= '9' || value[i] == '.') {
+		i++
+	}
+	if i == 0 {
+		return 0, errors.New("missing number")
+	}
+
+	n, err := strconv.ParseFloat(value[:i], 64)
+	if err != nil {
+		return 0, err
+	}
+	unit := strings.ToLower(strings.TrimSpace(value[i:]))
+	multiplier, ok := sizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit %q", value[i:])
+	}
+
+	size := math.Round(n * multiplier)
+	if size >= math.MaxInt64 {
+		return 0, errors.New("size out of range")
+	}
+	return int64(size), nil
+}
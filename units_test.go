@@ -0,0 +1,128 @@
+package ygggo_env
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int64
+		wantErr  bool
+	}{
+		{value: "512", expected: 512},
+		{value: "512B", expected: 512},
+		{value: "64k", expected: 64 << 10},
+		{value: "64K", expected: 64 << 10},
+		{value: "512MB", expected: 512e6},
+		{value: "512 mb", expected: 512e6},
+		{value: "1.5GiB", expected: 3 << 29},
+		{value: "2g", expected: 2 << 30},
+		{value: "1TB", expected: 1e12},
+		{value: "0.5KiB", expected: 512},
+		{value: "MB", wantErr: true},
+		{value: "10XB", wantErr: true},
+		{value: "-1MB", wantErr: true},
+		{value: "1.2.3MB", wantErr: true},
+		{value: "9999999PiB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			size, err := parseSize(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSize(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if size != tt.expected {
+				t.Errorf("parseSize(%q) = %d, want %d", tt.value, size, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetDuration(t *testing.T) {
+	t.Setenv("TEST_DURATION", "1h30m")
+	t.Setenv("TEST_DURATION_BARE", "250")
+	t.Setenv("TEST_DURATION_FRACTION", "1.5")
+
+	if got := GetDuration("TEST_DURATION", 0); got != 90*time.Minute {
+		t.Errorf("GetDuration() = %v, want 1h30m", got)
+	}
+	if got := GetDuration("TEST_DURATION_BARE", time.Second); got != time.Second {
+		t.Errorf("GetDuration() on bare number = %v, want default", got)
+	}
+	if got := GetDurationIn("TEST_DURATION_BARE", 0, time.Millisecond); got != 250*time.Millisecond {
+		t.Errorf("GetDurationIn() = %v, want 250ms", got)
+	}
+	if got := GetDurationIn("TEST_DURATION_FRACTION", 0, time.Second); got != 1500*time.Millisecond {
+		t.Errorf("GetDurationIn() = %v, want 1.5s", got)
+	}
+	if got := GetDurationIn("TEST_DURATION", 0, time.Second); got != 90*time.Minute {
+		t.Errorf("GetDurationIn() with unit suffix = %v, want 1h30m", got)
+	}
+	if got := GetDuration("TEST_DURATION_UNSET", 5*time.Second); got != 5*time.Second {
+		t.Errorf("GetDuration() on unset = %v, want default", got)
+	}
+
+	for _, value := range []string{"NaN", "Inf", "-Inf", "1e300"} {
+		t.Setenv("TEST_DURATION_BAD", value)
+		var ve *ValueError
+		if got, ok, err := LookupDurationIn("TEST_DURATION_BAD", time.Second); !ok || !errors.As(err, &ve) {
+			t.Errorf("LookupDurationIn(%q) = %v, %v, %v, want *ValueError", value, got, ok, err)
+		}
+	}
+}
+
+func TestGetSize(t *testing.T) {
+	t.Setenv("TEST_SIZE", "1.5GiB")
+	t.Setenv("TEST_SIZE_BAD", "big")
+
+	if got := GetSize("TEST_SIZE", 0); got != 3<<29 {
+		t.Errorf("GetSize() = %d, want %d", got, int64(3<<29))
+	}
+	if got := GetSize("TEST_SIZE_BAD", 1024); got != 1024 {
+		t.Errorf("GetSize() on invalid value = %d, want default", got)
+	}
+	if _, ok, err := LookupSize("TEST_SIZE_BAD"); !ok || err == nil {
+		t.Errorf("LookupSize() = %v, %v, want error", ok, err)
+	}
+}
+
+func TestGetTime(t *testing.T) {
+	t.Setenv("TEST_TIME", "2024-06-01T02:00:00+08:00")
+	t.Setenv("TEST_TIME_DATE", "2024-06-01")
+	t.Setenv("TEST_TIME_CLOCK", "02:30")
+
+	if got := GetTime("TEST_TIME", time.Time{}); !got.Equal(time.Date(2024, 5, 31, 18, 0, 0, 0, time.UTC)) {
+		t.Errorf("GetTime() = %v", got)
+	}
+	if got := GetTime("TEST_TIME_DATE", time.Time{}); !got.IsZero() {
+		t.Errorf("GetTime() with default layout = %v, want default", got)
+	}
+	if got := GetTime("TEST_TIME_DATE", time.Time{}, time.RFC3339, time.DateOnly); got.Day() != 1 || got.Month() != time.June {
+		t.Errorf("GetTime() with DateOnly layout = %v", got)
+	}
+	if got := GetTime("TEST_TIME_CLOCK", time.Time{}, "15:04"); got.Hour() != 2 || got.Minute() != 30 {
+		t.Errorf("GetTime() with clock layout = %v", got)
+	}
+}
+
+func TestGetLocation(t *testing.T) {
+	t.Setenv("TEST_LOCATION", "UTC")
+	t.Setenv("TEST_LOCATION_BAD", "Mars/Olympus")
+
+	if got := GetLocation("TEST_LOCATION", time.Local); got != time.UTC {
+		t.Errorf("GetLocation() = %v, want UTC", got)
+	}
+	if got := GetLocation("TEST_LOCATION_BAD", time.Local); got != time.Local {
+		t.Errorf("GetLocation() on unknown zone = %v, want default", got)
+	}
+	if _, ok, err := LookupLocation("TEST_LOCATION_BAD"); !ok || err == nil {
+		t.Errorf("LookupLocation() = %v, %v, want error", ok, err)
+	}
+	if got := GetLocation("TEST_LOCATION_UNSET", time.Local); got != time.Local {
+		t.Errorf("GetLocation() on unset = %v, want default", got)
+	}
+}
@@ -0,0 +1,236 @@
+package ygggo_env
+
+import (
+	"cmp"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Rules 是按环境变量名声明的校验规则，值的写法与 validate 标签相同
+// 多条规则用分号分隔，例如 "nonempty;min=1;max=65535"
+//
+// 支持的规则：
+//   - nonempty            值不能为空
+//   - required_if=K=V     环境变量 K 的值为 V 时，值不能为空 (布尔值按含义比较，true 与 1 等价)
+//   - min=N / max=N       N 是数字时值必须是数字并按大小比较，N 是时长时值必须是时长并按长短比较
+//   - len=N               字符数必须等于 N
+//   - oneof=a,b,c         值必须是列出的值之一
+//   - regexp=pattern      值必须匹配正则表达式
+//   - url                 值必须是带 scheme 和 host 的 URL
+//   - hostport            值必须是 host:port 格式
+//   - email               值必须是邮箱地址
+//
+// 除 nonempty 和 required_if 外，其他规则在值为空时不检查，因此可以用于可选的环境变量
+type Rules map[string]string
+
+// Violation 描述一个不满足校验规则的环境变量
+type Violation struct {
+	// Key 是环境变量的名称
+	Key string
+	// Value 是环境变量的值
+	Value string
+	// Rule 是不满足的规则，例如 oneof=debug,info
+	Rule string
+	// Message 是错误的详细描述
+	Message string
+}
+
+// Error 不包含 Value，避免把密钥等敏感值写入日志
+func (v *Violation) Error() string {
+	return fmt.Sprintf("%s: %s", v.Key, v.Message)
+}
+
+// Violations 是一次校验中发现的所有问题，按检查的顺序排列
+type Violations []*Violation
+
+func (v Violations) Error() string {
+	messages := make([]string, len(v))
+	for i, violation := range v {
+		messages[i] = violation.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap 使 errors.As 和 errors.Is 可以检查其中的每一个问题
+func (v Violations) Unwrap() []error {
+	errs := make([]error, len(v))
+	for i, violation := range v {
+		errs[i] = violation
+	}
+	return errs
+}
+
+// Validate 按规则检查当前的环境变量，没有问题时返回 nil，否则返回按环境变量名排序的 Violations
+func Validate(rules Rules) error {
+	return std.Validate(rules)
+}
+
+// Validate 与包级函数 Validate 相同，但从当前对象的变量中读取
+func (g getter) Validate(rules Rules) error {
+	keys := make([]string, 0, len(rules))
+	for key := range rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var violations Violations
+	for _, key := range keys {
+		violations = append(violations, validateValue(g, key, g.getenv(key), rules[key])...)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return violations
+}
+
+// LoadEnvAndValidate 加载 .env 文件后立即按规则检查环境变量
+// 加载失败时返回加载错误，否则返回 Validate 的结果
+func LoadEnvAndValidate(rules Rules) error {
+	if err := LoadEnv(); err != nil {
+		return err
+	}
+	return Validate(rules)
+}
+
+// validateValue 检查一个值是否满足规则字符串中的所有规则
+// l 用于 required_if 读取其他变量
+func validateValue(l Lookuper, key, value, rules string) Violations {
+	var violations Violations
+	for _, rule := range strings.Split(rules, ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		if message := checkRule(l, value, rule); message != "" {
+			violations = append(violations, &Violation{Key: key, Value: value, Rule: rule, Message: message})
+		}
+	}
+	return violations
+}
+
+// checkRule 检查单条规则，满足时返回空字符串，否则返回错误描述
+func checkRule(l Lookuper, value, rule string) string {
+	name, arg, _ := strings.Cut(rule, "=")
+
+	switch name {
+	case "nonempty":
+		if value == "" {
+			return "must not be empty"
+		}
+		return ""
+	case "required_if":
+		other, expected, ok := strings.Cut(arg, "=")
+		if !ok {
+			return fmt.Sprintf("invalid rule %q: expected required_if=KEY=value", rule)
+		}
+		if value == "" && sameValue(getenv(l, other), expected) {
+			return fmt.Sprintf("is required when %s=%s", other, expected)
+		}
+		return ""
+	}
+
+	if value == "" {
+		return ""
+	}
+
+	switch name {
+	case "min", "max":
+		cmp, problem, err := compareValue(value, arg)
+		if err != nil {
+			return fmt.Sprintf("invalid rule %q: %v", rule, err)
+		}
+		if problem != "" {
+			return problem
+		}
+		if name == "min" && cmp < 0 {
+			return fmt.Sprintf("must be at least %s", arg)
+		}
+		if name == "max" && cmp > 0 {
+			return fmt.Sprintf("must be at most %s", arg)
+		}
+	case "len":
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Sprintf("invalid rule %q: %v", rule, err)
+		}
+		if utf8.RuneCountInString(value) != n {
+			return fmt.Sprintf("must be exactly %d characters long", n)
+		}
+	case "oneof":
+		for _, allowed := range strings.Split(arg, ",") {
+			if value == strings.TrimSpace(allowed) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of %s", arg)
+	case "regexp":
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return fmt.Sprintf("invalid rule %q: %v", rule, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Sprintf("must match %s", arg)
+		}
+	case "url":
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be a URL with scheme and host"
+		}
+	case "hostport":
+		_, port, err := net.SplitHostPort(value)
+		if err != nil {
+			return "must be in host:port format"
+		}
+		if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+			return "must have a port between 1 and 65535"
+		}
+	case "email":
+		addr, err := mail.ParseAddress(value)
+		if err != nil || addr.Address != value {
+			return "must be an email address"
+		}
+	default:
+		return fmt.Sprintf("unknown rule %q", name)
+	}
+	return ""
+}
+
+// compareValue 比较值和规则参数，返回 -1、0 或 1
+// 参数是数字时把值当作数字比较，参数是时长时把值当作时长比较；
+// 值无法按参数的类型解析时返回 problem 描述问题，参数既不是数字也不是时长时返回错误
+func compareValue(value, arg string) (result int, problem string, err error) {
+	if a, err := strconv.ParseFloat(arg, 64); err == nil {
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, "must be a number", nil
+		}
+		return cmp.Compare(v, a), "", nil
+	}
+	if a, err := time.ParseDuration(arg); err == nil {
+		v, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, "must be a duration", nil
+		}
+		return cmp.Compare(v, a), "", nil
+	}
+	return 0, "", fmt.Errorf("%q is not a number or duration", arg)
+}
+
+// sameValue 判断环境变量的值是否等于 required_if 中期望的值，布尔值按含义比较
+func sameValue(actual, expected string) bool {
+	if actual == expected {
+		return true
+	}
+	a, err1 := parseBool(actual)
+	b, err2 := parseBool(expected)
+	return err1 == nil && err2 == nil && a == b
+}
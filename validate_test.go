@@ -0,0 +1,165 @@
+package ygggo_env
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckRule(t *testing.T) {
+	tests := []struct {
+		value string
+		rule  string
+		ok    bool
+	}{
+		{value: "x", rule: "nonempty", ok: true},
+		{value: "", rule: "nonempty", ok: false},
+		{value: "8080", rule: "min=1", ok: true},
+		{value: "0", rule: "min=1", ok: false},
+		{value: "70000", rule: "max=65535", ok: false},
+		{value: "1.5", rule: "max=2", ok: true},
+		{value: "10s", rule: "min=1s", ok: true},
+		{value: "500ms", rule: "min=1s", ok: false},
+		{value: "ab", rule: "min=3", ok: false},
+		{value: "abc", rule: "max=3", ok: false},
+		{value: "80a", rule: "min=1", ok: false},
+		{value: "80a", rule: "max=65535", ok: false},
+		{value: "10", rule: "max=1m", ok: false},
+		{value: "源滚滚", rule: "len=3", ok: true},
+		{value: "abcd", rule: "len=3", ok: false},
+		{value: "info", rule: "oneof=debug,info,warn", ok: true},
+		{value: "trace", rule: "oneof=debug,info,warn", ok: false},
+		{value: "v1.2.3", rule: `regexp=^v\d+\.\d+\.\d+$`, ok: true},
+		{value: "1.2", rule: `regexp=^v\d+`, ok: false},
+		{value: "https://example.com/x", rule: "url", ok: true},
+		{value: "example.com", rule: "url", ok: false},
+		{value: "localhost:3306", rule: "hostport", ok: true},
+		{value: "[::1]:80", rule: "hostport", ok: true},
+		{value: "localhost", rule: "hostport", ok: false},
+		{value: "localhost:99999", rule: "hostport", ok: false},
+		{value: "ops@example.com", rule: "email", ok: true},
+		{value: "ops at example.com", rule: "email", ok: false},
+		{value: "", rule: "email", ok: true},
+		{value: "", rule: "min=1", ok: true},
+		{value: "x", rule: "unknown", ok: false},
+		{value: "x", rule: "min=abc", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule+"/"+tt.value, func(t *testing.T) {
+			message := checkRule(std, tt.value, tt.rule)
+			if (message == "") != tt.ok {
+				t.Errorf("checkRule(%q, %q) = %q, want ok %v", tt.value, tt.rule, message, tt.ok)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Setenv("TEST_VAL_PORT", "0")
+	t.Setenv("TEST_VAL_LEVEL", "trace")
+	t.Setenv("TEST_VAL_HOST", "db.local:3306")
+	t.Setenv("TEST_VAL_TLS", "1")
+	t.Setenv("TEST_VAL_CERT", "")
+
+	err := Validate(Rules{
+		"TEST_VAL_PORT":  "min=1;max=65535",
+		"TEST_VAL_LEVEL": "oneof=debug,info,warn",
+		"TEST_VAL_HOST":  "nonempty;hostport",
+		"TEST_VAL_CERT":  "required_if=TEST_VAL_TLS=true",
+	})
+
+	var violations Violations
+	if !errors.As(err, &violations) {
+		t.Fatalf("Validate() error = %v, want Violations", err)
+	}
+
+	got := make([]string, len(violations))
+	for i, v := range violations {
+		got[i] = v.Key + " " + v.Rule
+	}
+	expected := []string{
+		"TEST_VAL_CERT required_if=TEST_VAL_TLS=true",
+		"TEST_VAL_LEVEL oneof=debug,info,warn",
+		"TEST_VAL_PORT min=1",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("violations = %v, want %v", got, expected)
+	}
+	if violations[1].Value != "trace" {
+		t.Errorf("violation value = %q, want trace", violations[1].Value)
+	}
+
+	t.Setenv("TEST_VAL_TLS", "false")
+	t.Setenv("TEST_VAL_PORT", "80")
+	t.Setenv("TEST_VAL_LEVEL", "info")
+	if err := Validate(Rules{
+		"TEST_VAL_PORT":  "min=1;max=65535",
+		"TEST_VAL_LEVEL": "oneof=debug,info,warn",
+		"TEST_VAL_CERT":  "required_if=TEST_VAL_TLS=true",
+	}); err != nil {
+		t.Errorf("Validate() failed: %v", err)
+	}
+}
+
+func TestBind_Validate(t *testing.T) {
+	type config struct {
+		Port  int    `env:"TEST_BV_PORT" validate:"min=1;max=65535"`
+		Level string `env:"TEST_BV_LEVEL" default:"info" validate:"oneof=debug,info,warn"`
+		Admin string `env:"TEST_BV_ADMIN" validate:"email"`
+		Name  string `env:"TEST_BV_NAME" validate:"nonempty"`
+	}
+	t.Setenv("TEST_BV_PORT", "70000")
+	t.Setenv("TEST_BV_ADMIN", "root")
+
+	var cfg config
+	err := Bind(&cfg)
+
+	var violations Violations
+	if !errors.As(err, &violations) {
+		t.Fatalf("Bind() error = %v, want Violations", err)
+	}
+	if len(violations) != 3 {
+		t.Fatalf("Bind() returned %d violations, want 3: %v", len(violations), violations)
+	}
+	for i, key := range []string{"TEST_BV_PORT", "TEST_BV_ADMIN", "TEST_BV_NAME"} {
+		if violations[i].Key != key {
+			t.Errorf("violation %d key = %s, want %s", i, violations[i].Key, key)
+		}
+	}
+}
+
+func TestLoadEnvAndValidate(t *testing.T) {
+	chdirTemp(t, "TEST_LAV_MODE=fast\n")
+	unsetAfter(t, "TEST_LAV_MODE")
+
+	err := LoadEnvAndValidate(Rules{"TEST_LAV_MODE": "oneof=slow,normal"})
+	var v *Violation
+	if !errors.As(err, &v) || v.Key != "TEST_LAV_MODE" {
+		t.Fatalf("LoadEnvAndValidate() error = %v, want violation for TEST_LAV_MODE", err)
+	}
+	if strings.Contains(err.Error(), "fast") {
+		t.Errorf("error message should not contain the value: %v", err)
+	}
+}
+
+func TestValidate_NotANumber(t *testing.T) {
+	env := New(WithVars(map[string]string{"PORT": "80a", "TIMEOUT": "soon"}))
+
+	err := env.Validate(Rules{"PORT": "min=1;max=65535", "TIMEOUT": "max=1m"})
+	var violations Violations
+	if !errors.As(err, &violations) {
+		t.Fatalf("Validate() error = %v, want Violations", err)
+	}
+
+	messages := make(map[string]string)
+	for _, v := range violations {
+		messages[v.Key] = v.Message
+	}
+	if messages["PORT"] != "must be a number" {
+		t.Errorf("PORT message = %q, want must be a number", messages["PORT"])
+	}
+	if messages["TIMEOUT"] != "must be a duration" {
+		t.Errorf("TIMEOUT message = %q, want must be a duration", messages["TIMEOUT"])
+	}
+}
@@ -0,0 +1,313 @@
+package ygggo_env
+
+import (
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// loadRecord 是一次成功的加载，重新加载时按原来的顺序重放
+type loadRecord struct {
+	files    fileSystem
+	paths    []string
+	overload bool
+}
+
+// loadLog 记录 Env 中成功的加载，以及由加载写入的变量
+// mu 同时保证加载、重新加载和 Set、Unset 依次执行
+type loadLog struct {
+	mu      sync.Mutex
+	records []loadRecord
+	// values 是由加载写入、之后没有被 Set 或 Unset 修改过的变量
+	values map[string]string
+}
+
+// add 记录一次成功的加载，调用者需要持有 mu
+func (l *loadLog) add(r loadRecord, changes []change) {
+	l.records = append(l.records, r)
+	if l.values == nil {
+		l.values = make(map[string]string)
+	}
+	for _, c := range changes {
+		l.values[c.key] = c.value
+	}
+}
+
+// watchedFile 是一个需要检查的文件，record 是它所属的加载在 records 中的下标
+type watchedFile struct {
+	record int
+	path   string
+}
+
+// watched 返回所有加载过的文件
+func (l *loadLog) watched() map[watchedFile]fileSystem {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	files := make(map[watchedFile]fileSystem)
+	for i, r := range l.records {
+		for _, path := range r.paths {
+			files[watchedFile{record: i, path: path}] = r.files
+		}
+	}
+	return files
+}
+
+// Diff 描述一次重新加载中发生变化的变量名，按字母顺序排列
+type Diff struct {
+	// Added 是新增的变量
+	Added []string
+	// Changed 是值发生变化的变量
+	Changed []string
+	// Removed 是从文件中删除、因此被删除的变量
+	Removed []string
+}
+
+// empty 判断是否没有任何变化
+func (d Diff) empty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0
+}
+
+// reload 按原来的顺序重新读取所有加载过的文件，把与上次结果的差异一次性写入，并发布新的 Snapshot
+// 任意文件读取或解析失败时不写入任何变化
+func (e *Env) reload() (Diff, error) {
+	var diff Diff
+	err := e.update(func() error {
+		var err error
+		diff, err = e.reloadFiles()
+		return err
+	})
+	return diff, err
+}
+
+// reloadFiles 计算并写入重新加载的差异，调用者需要持有 loads.mu
+// 之前由加载写入的变量在重新计算时视为不存在，因此文件中修改的值会生效；
+// 通过 Set 设置过的变量与进程中原有的变量一样，不会被不覆盖模式的加载修改
+func (e *Env) reloadFiles() (Diff, error) {
+	owned := e.loads.values
+	base := loadLookup(e.store)
+	next := make(map[string]string)
+	lookup := func(key string) (string, bool) {
+		if value, ok := next[key]; ok {
+			return value, true
+		}
+		if _, ok := owned[key]; ok {
+			return "", false
+		}
+		return base(key)
+	}
+
+	var records []provenanceRecord
+	for _, r := range e.loads.records {
+		plan, err := planLoad(lookup, r.files.readFile, r.paths, r.overload)
+		if err != nil {
+			return Diff{}, err
+		}
+		for _, c := range plan.changes {
+			next[c.key] = c.value
+		}
+		records = append(records, plan.records...)
+	}
+
+	var diff Diff
+	for key, value := range next {
+		if old, ok := owned[key]; !ok {
+			diff.Added = append(diff.Added, key)
+		} else if old != value {
+			diff.Changed = append(diff.Changed, key)
+		}
+	}
+	for key := range owned {
+		if _, ok := next[key]; !ok {
+			diff.Removed = append(diff.Removed, key)
+		}
+	}
+	if diff.empty() {
+		return diff, nil
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Changed)
+	sort.Strings(diff.Removed)
+
+	var changes []change
+	for _, key := range append(append([]string(nil), diff.Added...), diff.Changed...) {
+		changes = append(changes, change{key: key, value: next[key]})
+	}
+	for _, key := range diff.Removed {
+		changes = append(changes, change{key: key, unset: true})
+	}
+	if err := e.store.apply(changes); err != nil {
+		return Diff{}, err
+	}
+	e.loads.values = next
+
+	// 只为发生变化的变量补充来源记录，被删除的变量清除记录
+	updated := make(map[string]bool)
+	for _, c := range changes {
+		updated[c.key] = !c.unset
+	}
+	var changed []provenanceRecord
+	for _, r := range records {
+		if updated[r.key] {
+			changed = append(changed, r)
+		}
+	}
+	e.provenance.record(changed)
+	for _, key := range diff.Removed {
+		e.provenance.forget(key)
+	}
+	return diff, nil
+}
+
+// Watcher 定期检查加载过的 .env 文件，文件变化时重新加载并通知回调
+// 通过比较文件的修改时间、大小以及 os.SameFile 判断文件是否变化，不依赖任何外部库，
+// 因此也能发现 Kubernetes ConfigMap 卷通过替换符号链接完成的更新
+type Watcher struct {
+	env      *Env
+	interval time.Duration
+
+	mu       sync.Mutex
+	states   map[watchedFile]fileState
+	onChange []func(Diff)
+	onError  []func(error)
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Watch 开始每隔 interval 检查一次通过 LoadEnv、Load、LoadFS 等函数加载过的文件，
+// 文件变化时按原来的顺序和优先级重新加载所有文件，并把差异一次性写入环境变量：
+//
+//	w := gge.Watch(5 * time.Second)
+//	defer w.Close()
+//	w.OnChange(func(d gge.Diff) {
+//		log.Printf("reloaded: added %v, changed %v, removed %v", d.Added, d.Changed, d.Removed)
+//	})
+//
+// 任意文件读取或解析失败时不写入任何变化，错误交给 OnError 注册的回调
+// interval 必须大于 0，否则 Watch 会在调用者的 goroutine 中 panic
+func Watch(interval time.Duration) *Watcher {
+	return std.Watch(interval)
+}
+
+// Watch 与包级函数 Watch 相同，但检查 Env 加载过的文件
+func (e *Env) Watch(interval time.Duration) *Watcher {
+	if interval <= 0 {
+		panic("ygggo_env: non-positive interval for Watch")
+	}
+	w := &Watcher{
+		env:      e,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	w.states = w.stat()
+	go w.run()
+	return w
+}
+
+// OnChange 注册重新加载后调用的回调，只有变量确实发生变化时才会调用
+func (w *Watcher) OnChange(fn func(Diff)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onChange = append(w.onChange, fn)
+}
+
+// OnError 注册重新加载失败时调用的回调，失败时环境变量保持不变
+func (w *Watcher) OnError(fn func(error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onError = append(w.onError, fn)
+}
+
+// Check 立即检查一次文件，文件变化时重新加载并调用回调
+// 返回本次写入的差异，文件没有变化时返回空的 Diff
+func (w *Watcher) Check() (Diff, error) {
+	w.mu.Lock()
+	states := w.stat()
+	changed := false
+	for file, state := range states {
+		if old, ok := w.states[file]; ok && !old.equal(state) {
+			changed = true
+		}
+	}
+	w.states = states
+	onChange := w.onChange
+	onError := w.onError
+	w.mu.Unlock()
+
+	if !changed {
+		return Diff{}, nil
+	}
+
+	diff, err := w.env.reload()
+	if err != nil {
+		for _, fn := range onError {
+			fn(err)
+		}
+		return Diff{}, err
+	}
+	if !diff.empty() {
+		for _, fn := range onChange {
+			fn(diff)
+		}
+	}
+	return diff, nil
+}
+
+// Close 停止检查，等待正在进行的检查结束
+func (w *Watcher) Close() {
+	w.closeOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+}
+
+func (w *Watcher) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.Check()
+		}
+	}
+}
+
+// stat 返回所有加载过的文件的当前状态
+func (w *Watcher) stat() map[watchedFile]fileState {
+	states := make(map[watchedFile]fileState)
+	for file, files := range w.env.loads.watched() {
+		state := fileState{sameFile: files.sameFile}
+		if info, err := files.stat(file.path); err == nil {
+			state.info = info
+		}
+		states[file] = state
+	}
+	return states
+}
+
+// fileState 是文件在某一时刻的状态
+type fileState struct {
+	// info 是文件的信息，文件不存在或无法读取时为 nil
+	info fs.FileInfo
+	// sameFile 为 true 时还会通过 os.SameFile 比较
+	sameFile bool
+}
+
+// equal 判断文件是否没有变化
+func (s fileState) equal(o fileState) bool {
+	if s.info == nil || o.info == nil {
+		return s.info == nil && o.info == nil
+	}
+	if s.sameFile && !os.SameFile(s.info, o.info) {
+		return false
+	}
+	return s.info.ModTime().Equal(o.info.ModTime()) && s.info.Size() == o.info.Size() && s.info.Mode() == o.info.Mode()
+}
@@ -0,0 +1,208 @@
+package ygggo_env
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// rewrite 修改文件内容，并把修改时间往后推，避免文件系统的时间精度导致变化被忽略
+// 新内容先写入临时文件再重命名覆盖原文件，运行中的 Watcher 不会读到写了一半的文件
+func rewrite(t *testing.T, path, content string) {
+	t.Helper()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", tmp, err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(tmp, later, later); err != nil {
+		t.Fatalf("Failed to touch %s: %v", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatalf("Failed to replace %s: %v", path, err)
+	}
+}
+
+func TestWatcher_Check(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{
+		".env":       "HOST=base\nPORT=1\nOLD=1\nPRESET=file\n",
+		".env.local": "HOST=local\n",
+	})
+	base, local := paths[".env"], paths[".env.local"]
+
+	env := New(WithVars(map[string]string{"PRESET": "preset"}))
+	if err := env.Load(local, base); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	w := env.Watch(time.Hour)
+	defer w.Close()
+	var notified []Diff
+	w.OnChange(func(d Diff) {
+		notified = append(notified, d)
+	})
+
+	if diff, err := w.Check(); err != nil || !diff.empty() {
+		t.Fatalf("Check() without changes = %+v, %v", diff, err)
+	}
+
+	rewrite(t, base, "HOST=base\nPORT=2\nNEW=1\nPRESET=changed\n")
+	rewrite(t, local, "\n")
+
+	diff, err := w.Check()
+	if err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+	expected := Diff{Added: []string{"NEW"}, Changed: []string{"HOST", "PORT"}, Removed: []string{"OLD"}}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("Check() = %+v, want %+v", diff, expected)
+	}
+	if len(notified) != 1 || !reflect.DeepEqual(notified[0], expected) {
+		t.Errorf("OnChange() received %+v", notified)
+	}
+
+	want := map[string]string{"HOST": "base", "PORT": "2", "NEW": "1", "PRESET": "preset"}
+	for key, value := range want {
+		if got := env.GetStr(key, ""); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+	if _, ok := env.LookupEnv("OLD"); ok {
+		t.Errorf("OLD should be removed after reload")
+	}
+	if origin, ok := env.Origin("HOST"); !ok || origin.File != base {
+		t.Errorf("Origin() after reload = %v, %v", origin, ok)
+	}
+}
+
+func TestWatcher_ParseErrorAppliesNothing(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{"a.env": "A=1\n", "b.env": "B=1\n"})
+
+	env := New()
+	if err := env.Load(paths["a.env"], paths["b.env"]); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	w := env.Watch(time.Hour)
+	defer w.Close()
+	var errs []error
+	w.OnError(func(err error) {
+		errs = append(errs, err)
+	})
+
+	rewrite(t, paths["a.env"], "A=2\n")
+	rewrite(t, paths["b.env"], "B=\"unterminated\n")
+	if _, err := w.Check(); err == nil {
+		t.Fatalf("Check() should fail for an invalid file")
+	}
+	if len(errs) != 1 {
+		t.Errorf("OnError() called %d times, want 1", len(errs))
+	}
+	if got := env.GetStr("A", ""); got != "1" {
+		t.Errorf("A = %q, a failed reload must not apply anything", got)
+	}
+
+	rewrite(t, paths["b.env"], "B=2\n")
+	diff, err := w.Check()
+	if err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+	if !reflect.DeepEqual(diff.Changed, []string{"A", "B"}) {
+		t.Errorf("Check() = %+v", diff)
+	}
+}
+
+func TestWatcher_SymlinkSwap(t *testing.T) {
+	// 模拟 Kubernetes ConfigMap 卷：.env -> ..data/.env，..data -> 带时间戳的目录
+	dir := t.TempDir()
+	for name, content := range map[string]string{"v1": "TOKEN=aaaa\n", "v2": "TOKEN=bbbb\n"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, ".env"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 两个版本的大小和修改时间相同，只能通过 os.SameFile 发现变化
+	same := time.Now().Add(-time.Hour)
+	for _, name := range []string{"v1", "v2"} {
+		if err := os.Chtimes(filepath.Join(dir, name, ".env"), same, same); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("v1", filepath.Join(dir, "..data")); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+	path := filepath.Join(dir, ".env")
+	if err := os.Symlink(filepath.Join("..data", ".env"), path); err != nil {
+		t.Fatal(err)
+	}
+
+	env := New()
+	if err := env.Load(path); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	w := env.Watch(time.Hour)
+	defer w.Close()
+
+	if err := os.Symlink("v2", filepath.Join(dir, "..data_tmp")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := w.Check()
+	if err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+	if !reflect.DeepEqual(diff.Changed, []string{"TOKEN"}) || env.GetStr("TOKEN", "") != "bbbb" {
+		t.Errorf("Check() = %+v, TOKEN = %q", diff, env.GetStr("TOKEN", ""))
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+	paths := writeEnvFiles(t, map[string]string{".env": "LEVEL=info\n"})
+
+	env := New()
+	if err := env.Load(paths[".env"]); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	w := env.Watch(10 * time.Millisecond)
+	changes := make(chan Diff, 1)
+	w.OnChange(func(d Diff) {
+		changes <- d
+	})
+
+	rewrite(t, paths[".env"], "LEVEL=debug\n")
+	select {
+	case d := <-changes:
+		if !reflect.DeepEqual(d.Changed, []string{"LEVEL"}) {
+			t.Errorf("OnChange() received %+v", d)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Watcher did not report the change")
+	}
+
+	w.Close()
+	w.Close()
+	if got := env.GetStr("LEVEL", ""); got != "debug" {
+		t.Errorf("LEVEL = %q, want debug", got)
+	}
+}
+
+func TestWatcher_InvalidInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		t.Run(interval.String(), func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Watch(%v) should panic", interval)
+				}
+			}()
+			New().Watch(interval)
+		})
+	}
+}